@@ -1,16 +1,26 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type callSiteInterface interface {
 	Name() string
 	Lifetime() lifetime
 	Deps() []string
-	Build(s *Scope) (any, error)
+	DepTypes() []reflect.Type
+	Options() serviceOptions
+	Constructions() uint64
+	Clone() callSiteInterface
+	Build(ctx context.Context, s *Scope) (any, error)
 	BuildCallSite(c *Container) error
 }
 
@@ -20,41 +30,103 @@ type callSite[T any] struct {
 	dependencyNames  []string
 	dependencies     []callSiteInterface
 	initMethod       reflect.Method
+	options          serviceOptions
+	container        *Container
+	validate         bool
 	built            bool
-	once             sync.Once
+	mu               sync.Mutex
+	building         chan struct{} // closed when the singleton construction in progress ends
+	constructed      atomic.Bool
+	constructions    atomic.Uint64
+	failures         int
+	retryAt          time.Time
 	constructorError error
-	instance         *T
+	instance         any          // *T, or a pointer to typ for open generic instantiations
+	typ              reflect.Type // set for instantiations of open generic types, see AddOpenGenericSingleton
 }
 
-func (c *callSite[T]) Name() string       { return c.name }
-func (c *callSite[T]) Lifetime() lifetime { return c.lifetime }
-func (c *callSite[T]) Deps() []string     { return c.dependencyNames }
+func (c *callSite[T]) Name() string            { return c.name }
+func (c *callSite[T]) Lifetime() lifetime      { return c.lifetime }
+func (c *callSite[T]) Deps() []string          { return c.dependencyNames }
+func (c *callSite[T]) Options() serviceOptions { return c.options }
+func (c *callSite[T]) Constructions() uint64   { return c.constructions.Load() }
 
-func (c *callSite[T]) Build(s *Scope) (any, error) {
+// Clone returns an unbuilt copy of the registration. Values share their value, except
+// values implementing valueCloner.
+func (c *callSite[T]) Clone() callSiteInterface {
+	clone := &callSite[T]{
+		name:            c.name,
+		lifetime:        c.lifetime,
+		dependencyNames: c.dependencyNames,
+		initMethod:      c.initMethod,
+		options:         c.options,
+		validate:        c.validate,
+		typ:             c.typ,
+	}
+	if c.lifetime == Value {
+		clone.instance = c.instance
+		if cloner, ok := any(c.getValue()).(valueCloner); ok {
+			value := cloner.cloneValue().(T)
+			clone.instance = &value
+		}
+		clone.constructed.Store(true)
+	}
+	return clone
+}
+
+// DepTypes returns the types of the Init parameters, in the order of Deps.
+func (c *callSite[T]) DepTypes() []reflect.Type {
+	if c.initMethod.Type == nil {
+		return nil
+	}
+	types := make([]reflect.Type, 0, len(c.dependencyNames))
+	for i := 1; i < c.initMethod.Type.NumIn(); i++ {
+		types = append(types, c.initMethod.Type.In(i))
+	}
+	return types
+}
+
+func (c *callSite[T]) Build(ctx context.Context, s *Scope) (any, error) {
 	var err error
 	var instance any
-	if c.Lifetime() == Value {
-		instance = c.getValue()
+	if c.container != nil && len(c.container.interceptors) > 0 {
+		info := ResolveInfo{Name: c.Name(), Lifetime: c.Lifetime(), Scope: s}
+		instance, err = c.container.intercept(ctx, info, func(ctx context.Context) (any, error) {
+			return c.resolve(ctx, s)
+		})
 	} else {
-		instance, err = c.build(s)
-		if err != nil {
-			return nil, err
-		}
+		instance, err = c.resolve(ctx, s)
+	}
+	if err != nil {
+		return nil, withResolutionStep(err, ResolutionStep{Name: c.Name(), Lifetime: c.Lifetime()})
 	}
 	return instance, nil
 }
-func (c *callSite[T]) build(s *Scope) (*T, error) {
+
+func (c *callSite[T]) resolve(ctx context.Context, s *Scope) (any, error) {
+	if c.Lifetime() == Value {
+		return c.getValue(), nil
+	}
+	instance, err := c.build(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+func (c *callSite[T]) build(ctx context.Context, s *Scope) (any, error) {
 	switch c.lifetime {
 	case HostedService:
 		fallthrough
 	case Value:
 		fallthrough
 	case Singleton:
-		return c.buildSingleton()
+		return c.buildSingleton(ctx)
 	case Transient:
-		return c.buildTransient()
+		return c.buildTransient(ctx, s)
 	case Scoped:
-		return c.buildScoped(s)
+		return c.buildScoped(ctx, s)
+	case TenantSingleton:
+		return c.buildTenantSingleton(ctx, s)
 	default:
 		return nil, fmt.Errorf("%w: for %s", ErrUnknownLifetime, c.Name())
 	}
@@ -62,31 +134,89 @@ func (c *callSite[T]) build(s *Scope) (*T, error) {
 
 func activatorFor[T any]() *T { return new(T) }
 
-func (c *callSite[T]) constructor(s *Scope) (*T, error) {
+// activate allocates the instance to initialize.
+func (c *callSite[T]) activate() any {
+	if c.typ != nil {
+		return reflect.New(c.typ).Interface()
+	}
+	return activatorFor[T]()
+}
+
+func (c *callSite[T]) constructor(ctx context.Context, s *Scope) (any, error) {
+	ctx, span := c.container.startSpan(ctx, c.name, c.lifetime, s)
+	instance, duration, initialized, err := c.construct(ctx, s)
+	span.End(err)
+	if err == nil {
+		c.constructions.Add(1)
+	}
+	if initialized {
+		c.container.observeConstruction(c.name, c.lifetime, duration, err)
+	}
+	if c.container != nil && c.container.logger != nil {
+		attrs := append(serviceAttrs(c.name, c.lifetime), slog.Duration("duration", duration))
+		if s != nil {
+			attrs = append(attrs, slog.Uint64("scope", s.id))
+		}
+		if err != nil {
+			c.container.log(slog.LevelWarn, "service construction failed", append(attrs, slog.Any("error", err))...)
+		} else {
+			c.container.log(slog.LevelDebug, "service constructed", attrs...)
+		}
+	}
+	return instance, err
+}
+
+// construct resolves the dependencies and initializes a new instance. The returned
+// duration covers Init and validation only, not the resolution of the dependencies.
+// initialized is false when Init was not called because a dependency failed or the
+// resolution was aborted.
+func (c *callSite[T]) construct(ctx context.Context, s *Scope) (instance any, duration time.Duration, initialized bool, err error) {
 	// Build dependencies
 	deps := make([]any, len(c.dependencies))
 	for i, v := range c.dependencies {
-		dep, err := v.Build(s)
+		if err := ctx.Err(); err != nil {
+			return nil, 0, false, fmt.Errorf("resolution of %s aborted: %w", c.Name(), err)
+		}
+		if v == nil || (c.dependencyNames[i] == contextDependencyName && hasResolutionContext(ctx)) {
+			deps[i] = resolutionContext(ctx)
+			continue
+		}
+		dep, err := v.Build(ctx, s)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build dependency %s: %w", v.Name(), err)
+			return nil, 0, false, err
 		}
 		deps[i] = dep
 	}
 
-	resolved := activatorFor[T]()
+	if err := ctx.Err(); err != nil {
+		return nil, 0, false, fmt.Errorf("resolution of %s aborted: %w", c.Name(), err)
+	}
+	begin := time.Now()
+	instance, err = c.initialize(deps)
+	return instance, time.Since(begin), true, err
+}
+
+// initialize creates an instance and calls its Init method with the resolved dependencies.
+func (c *callSite[T]) initialize(deps []any) (any, error) {
+	resolved := c.activate()
 
 	if ok, err := c.tryFastInit(resolved, deps); ok {
 		if err != nil {
 			err = fmt.Errorf("%w: for %s: %w", ErrFailedToBuildDependency, c.Name(), err)
 			return nil, err
 		}
-		return resolved, nil
+		return c.validated(resolved)
 	}
 
 	// Slow path: reflection
 	args := make([]reflect.Value, 0, len(deps)+1)
 	args = append(args, reflect.ValueOf(resolved))
-	for _, dep := range deps {
+	for i, dep := range deps {
+		if dep == nil {
+			// nil interface values registered with AddValue
+			args = append(args, reflect.Zero(c.initMethod.Type.In(i+1)))
+			continue
+		}
 		args = append(args, reflect.ValueOf(dep))
 	}
 
@@ -99,55 +229,201 @@ func (c *callSite[T]) constructor(s *Scope) (*T, error) {
 		return nil, err
 	}
 
+	return c.validated(resolved)
+}
+
+// validated checks the invariants of a constructed instance.
+func (c *callSite[T]) validated(resolved any) (any, error) {
+	if !c.validate {
+		return resolved, nil
+	}
+	if err := validateValue(resolved); err != nil {
+		return nil, fmt.Errorf("%w: for %s: %w", ErrFailedToBuildDependency, c.Name(), err)
+	}
 	return resolved, nil
 }
 
-func (c *callSite[T]) buildSingleton() (*T, error) {
-	c.once.Do(func() {
-		c.instance, c.constructorError = c.constructor(nil)
-	})
-	return c.instance, c.constructorError
+func (c *callSite[T]) buildSingleton(ctx context.Context) (any, error) {
+	for {
+		if c.constructed.Load() {
+			c.cacheHit(nil)
+			return c.instance, nil
+		}
+		c.mu.Lock()
+		if c.constructed.Load() {
+			c.mu.Unlock()
+			c.cacheHit(nil)
+			return c.instance, nil
+		}
+		if building := c.building; building != nil {
+			// Another caller is constructing the instance: wait for it unless ctx is done first.
+			c.mu.Unlock()
+			select {
+			case <-building:
+				continue
+			case <-ctx.Done():
+				return nil, fmt.Errorf("resolution of %s aborted: %w", c.Name(), ctx.Err())
+			}
+		}
+		if c.constructorError != nil && !c.options.constructionRetry.shouldRetry(c.retryAt) {
+			err := c.constructorError
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.building = make(chan struct{})
+		c.mu.Unlock()
+		return c.constructSingleton(ctx)
+	}
+}
+
+// constructSingleton constructs the singleton instance while c.building is set, and
+// wakes up the callers waiting for it.
+func (c *callSite[T]) constructSingleton(ctx context.Context) (instance any, err error) {
+	defer func() {
+		c.mu.Lock()
+		close(c.building)
+		c.building = nil
+		c.mu.Unlock()
+	}()
+
+	instance, err = c.constructor(ctx, nil)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		if ctx.Err() != nil {
+			// The resolution was cancelled: do not cache the failure of this caller.
+			return nil, err
+		}
+		c.constructorError = err
+		c.retryAt = time.Now().Add(c.options.constructionRetry.backoff(c.failures))
+		c.failures++
+		return nil, err
+	}
+	c.instance = instance
+	c.constructorError = nil
+	c.constructed.Store(true)
+	return instance, nil
+}
+
+// cacheHit records the resolution of an already constructed instance.
+func (c *callSite[T]) cacheHit(s *Scope) {
+	c.container.observeCacheHit(c.name, c.lifetime)
+	if c.container.logEnabled(slog.LevelDebug) {
+		attrs := serviceAttrs(c.name, c.lifetime)
+		if s != nil {
+			attrs = append(attrs, slog.Uint64("scope", s.id))
+		}
+		c.container.log(slog.LevelDebug, "service resolved from cache", attrs...)
+	}
 }
 
 func (c *callSite[T]) getValue() T {
-	return *c.instance
+	return *c.instance.(*T)
 }
 
-func (c *callSite[T]) buildTransient() (*T, error) {
-	return c.constructor(nil)
+func (c *callSite[T]) buildTransient(ctx context.Context, s *Scope) (any, error) {
+	return c.constructor(ctx, s)
 }
 
-func (c *callSite[T]) buildScoped(s *Scope) (*T, error) {
+func (c *callSite[T]) buildScoped(ctx context.Context, s *Scope) (any, error) {
 	if s == nil {
 		return nil, ErrScopeIsNil
 	}
 	if s.isGlobal {
 		return nil, ErrScopedDependencyInGlobalScope
 	}
+	if s.closed {
+		return nil, fmt.Errorf("%w: scope %d", ErrScopeClosed, s.id)
+	}
 	if s.instances[c.name] != nil {
-		return s.instances[c.name].(*T), nil
+		c.cacheHit(s)
+		return s.instances[c.name], nil
 	}
-	obj, err := c.constructor(s)
+	obj, err := c.constructor(ctx, s)
 	if err != nil {
 		return nil, err
 	}
 	s.instances[c.name] = obj
+	s.created = append(s.created, c.name)
 	return obj, nil
 }
 
+func (c *callSite[T]) buildTenantSingleton(ctx context.Context, s *Scope) (any, error) {
+	if s == nil {
+		return nil, ErrScopeIsNil
+	}
+	if !s.hasTenant {
+		return nil, fmt.Errorf("%w: for %s", ErrScopeWithoutTenant, c.Name())
+	}
+	for {
+		instances, entry := c.container.tenantEntry(s.tenant, c.name)
+		instance, err := c.buildTenantEntry(ctx, s, instances, entry)
+		if !errors.Is(err, errTenantEvicted) {
+			return instance, err
+		}
+	}
+}
+
+// buildTenantEntry returns the instance of entry, constructing it if needed.
+// It returns errTenantEvicted when the tenant was evicted in the meantime.
+func (c *callSite[T]) buildTenantEntry(ctx context.Context, s *Scope, instances *tenantInstances, entry *tenantEntry) (any, error) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.constructed {
+		instances.mu.Lock()
+		evicted := instances.evicted
+		instances.mu.Unlock()
+		if evicted {
+			return nil, errTenantEvicted
+		}
+		c.cacheHit(s)
+		return entry.instance, nil
+	}
+	instance, err := c.constructor(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	instances.mu.Lock()
+	evicted := instances.evicted
+	if !evicted {
+		entry.instance = instance
+		entry.constructed = true
+		instances.created = append(instances.created, c.name)
+	}
+	instances.mu.Unlock()
+	if evicted {
+		// Constructed too late to be closed by EvictTenant
+		if closer, ok := instance.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				c.container.log(slog.LevelWarn, "failed to close evicted tenant instance",
+					append(serviceAttrs(c.name, c.lifetime), slog.String("tenant", s.tenant), slog.Any("error", err))...)
+			}
+		}
+		return nil, errTenantEvicted
+	}
+	return instance, nil
+}
+
 func (c *callSite[T]) BuildCallSite(container *Container) error {
 	if c.built {
 		return nil
 	}
+	c.container = container
 
 	dependencies := make([]callSiteInterface, 0, len(c.dependencyNames))
 
-	for _, depName := range c.dependencyNames {
+	for i, depName := range c.dependencyNames {
 		// depName = strings.TrimPrefix(depName, "*")
-		site, ok := container.callSitesRegistry[depName]
-		depLife := site.Lifetime()
-		if (c.lifetime == Singleton || c.lifetime == HostedService) && depLife == Scoped {
-			return fmt.Errorf("%w: %s is Scoped for %s is Singleton", ErrCaptiveDependency, depName, c.Name())
+		site, ok := container.lookup(depName)
+		if !ok && depName == contextDependencyName {
+			// Not registered: the resolution context is injected
+			dependencies = append(dependencies, nil)
+			continue
+		}
+		if !ok && c.initMethod.Func.IsValid() && isValueKind(c.initMethod.Type.In(i+1).Kind()) {
+			return fmt.Errorf("%w: %s not found for %s. Values of kind %s should be registered with AddValue",
+				ErrDependencyNotFound, depName, c.Name(), c.initMethod.Type.In(i+1).Kind())
 		}
 		if !ok {
 			return fmt.Errorf("%w: %s not found for %s", ErrDependencyNotFound, depName, c.Name())
@@ -157,3 +433,8 @@ func (c *callSite[T]) BuildCallSite(container *Container) error {
 	c.dependencies = dependencies
 	return nil
 }
+
+// isValueKind reports whether Init parameters of kind k can only be provided by AddValue.
+func isValueKind(k reflect.Kind) bool {
+	return k != reflect.Struct && k != reflect.Pointer && k != reflect.Interface
+}
@@ -0,0 +1,88 @@
+package container
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// NewChild creates a container whose registrations fall back to the registrations of c.
+//
+// c must be built. The child can add registrations, including registrations overriding
+// those of c, and has its own singletons, hosted services, health checks, lifecycle and
+// Build validation, which takes the registrations of c into account. Settings such as the
+// logger, metrics, tracer, interceptors and lifetime rules are inherited.
+//
+// Services registered in c keep being resolved by c: singletons of c are shared by all its
+// children, and dependencies of services registered in c are resolved from c even when a
+// child overrides them.
+//
+// Example:
+//
+//	base.Build()
+//	tenant := base.NewChild()
+//	AddValue(tenant, TenantID("acme"))
+//	AddSingleton[IBilling, TenantBilling](tenant)
+//	tenant.Build()
+func (c *Container) NewChild() *Container {
+	if !c.built {
+		panic(fmt.Errorf("%w: You should call Build() before NewChild()", ErrContainerNotBuilt))
+	}
+	child := &Container{
+		parent:            c,
+		callSitesRegistry: make(map[string]callSiteInterface),
+		interceptors:      slices.Clone(c.interceptors),
+		logger:            c.logger,
+		metrics:           c.metrics,
+		tracer:            c.tracer,
+		lifetimeRules:     c.lifetimeRules,
+	}
+	child.global = &Scope{
+		Container: child,
+		isGlobal:  true,
+		instances: make(map[string]any),
+	}
+	return child
+}
+
+// Parent returns the container c was created from with NewChild, or nil.
+func (c *Container) Parent() *Container { return c.parent }
+
+// lookup returns the call site registered under name in c or its ancestors.
+func (c *Container) lookup(name string) (callSiteInterface, bool) {
+	for ; c != nil; c = c.parent {
+		if site, ok := c.callSitesRegistry[name]; ok {
+			return site, true
+		}
+	}
+	return nil, false
+}
+
+// lookupFor returns the call site the dependency name of site resolves to. Dependencies
+// are resolved from the container site is registered in, ignoring overrides of children.
+func (c *Container) lookupFor(site callSiteInterface, name string) (callSiteInterface, bool) {
+	owner := c
+	for owner.parent != nil && !owner.owns(site) {
+		owner = owner.parent
+	}
+	return owner.lookup(name)
+}
+
+// registrations returns the registry of c merged with the registries of its ancestors.
+func (c *Container) registrations() map[string]callSiteInterface {
+	if c.parent == nil {
+		return c.callSitesRegistry
+	}
+	merged := maps.Clone(c.parent.registrations())
+	if merged == nil {
+		merged = make(map[string]callSiteInterface)
+	}
+	maps.Copy(merged, c.callSitesRegistry)
+	return merged
+}
+
+// owns reports whether site is registered in c rather than in one of its ancestors.
+func (c *Container) owns(site callSiteInterface) bool {
+	registered, ok := c.callSitesRegistry[site.Name()]
+	return ok && registered == site
+}
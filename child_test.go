@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type TenantService struct {
+	Counter *Counter
+	A       AInterface
+}
+
+func (s *TenantService) Init(counter *Counter, c *C) error {
+	s.Counter = counter
+	return nil
+}
+
+type ChildSingleton struct{ C *C }
+
+func (s *ChildSingleton) Init(c *C) error { s.C = c; return nil }
+
+func TestChildSharesParentSingletons(t *testing.T) {
+	parent := BuildContainer()
+	child := parent.NewChild()
+	AddScopedWithoutInterface[TenantService](child)
+	AddHostedService[CountingHostedService](child)
+	child.Build()
+
+	parentB, err := RequireService[BInterface](parent)
+	if err != nil {
+		t.Fatalf("Failed to resolve B from parent: %v", err)
+	}
+	childB, err := RequireService[BInterface](child)
+	if err != nil || childB != parentB {
+		t.Errorf("Expected the parent singleton to be shared, got %v, %v", childB, err)
+	}
+
+	scope := child.CreateScope()
+	service, err := RequireServicePtrForScope[TenantService](scope)
+	if err != nil {
+		t.Fatalf("Failed to resolve TenantService: %v", err)
+	}
+	parentCounter, _ := RequireServicePtr[Counter](parent)
+	if service.Counter != parentCounter {
+		t.Error("Expected the parent Counter to be injected")
+	}
+	if _, err := RequireServicePtr[TenantService](parent); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected child registrations to be invisible to the parent, got %v", err)
+	}
+
+	if err := child.StartAsync(context.Background()); err != nil {
+		t.Fatalf("Failed to start child: %v", err)
+	}
+	if hosted, _ := RequireServicePtr[CountingHostedService](child); hosted.startCount != 1 {
+		t.Errorf("Expected the hosted service of the child to start")
+	}
+	if child.Lifetime() == parent.Lifetime() {
+		t.Error("Expected the child to have its own lifetime")
+	}
+	if child.Parent() != parent {
+		t.Error("Expected Parent to return the parent")
+	}
+}
+
+func TestChildOverridesRegistrations(t *testing.T) {
+	parent := BuildContainer()
+	child := parent.NewChild()
+	tenantCounter := &Counter{I: 100}
+	AddValue(child, tenantCounter)
+	AddScopedWithoutInterface[TenantService](child)
+	child.Build()
+
+	service, err := RequireServicePtrForScope[TenantService](child.CreateScope())
+	if err != nil {
+		t.Fatalf("Failed to resolve TenantService: %v", err)
+	}
+	if service.Counter != tenantCounter {
+		t.Error("Expected the child Counter to be injected in child services")
+	}
+	// A is registered in the parent and keeps using the parent Counter
+	if _, err := RequireServicePtr[A](child); err != nil {
+		t.Fatalf("Failed to resolve A: %v", err)
+	}
+	if tenantCounter.I != 100 {
+		t.Errorf("Expected A to be built with the parent Counter, got tenant Counter %d", tenantCounter.I)
+	}
+
+	for _, d := range child.Describe() {
+		if d.Type == "container.A" && !d.Inherited || d.Type == "*container.Counter" && d.Inherited {
+			t.Errorf("Unexpected Inherited in %+v", d)
+		}
+	}
+}
+
+func TestChildBuildValidatesAgainstParent(t *testing.T) {
+	child := BuildContainer().NewChild()
+	AddSingletonWithoutInterface[ChildSingleton](child)
+
+	if err := buildPanic(child); !errors.Is(err, ErrCaptiveDependency) {
+		t.Errorf("Expected captive dependency on the parent Scoped C, got %v", err)
+	}
+}
+
+func TestNewChildRequiresBuiltParent(t *testing.T) {
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrContainerNotBuilt) {
+			t.Errorf("Expected ErrContainerNotBuilt, got %v", err)
+		}
+	}()
+	GetContainer().NewChild()
+}
@@ -0,0 +1,123 @@
+package container
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// valueCloner is implemented by values holding state of the container they are registered
+// in, such as options monitors, which are hosted services. Clone registers their copy
+// instead of sharing them, so starting a clone does not start the original.
+type valueCloner interface {
+	cloneValue() any
+}
+
+// Clone returns a copy of an unbuilt container with the same registrations and settings.
+//
+// Registrations added to or overridden in the clone do not affect c, and the clone
+// constructs its own instances. Values registered with AddValue are shared, as are
+// the logger, metrics collector, tracer, interceptors and lifecycle listeners. Options
+// monitors registered with ConfigureMonitor are copied with their current value and
+// without listeners.
+func (c *Container) Clone() *Container {
+	if c.built {
+		panic(fmt.Errorf("%w: Cannot clone a container after Build()", ErrContainerAlreadyBuilt))
+	}
+	clone := &Container{
+		parent:             c.parent,
+		lifecycleListeners: slices.Clone(c.lifecycleListeners),
+		interceptors:       slices.Clone(c.interceptors),
+		logger:             c.logger,
+		metrics:            c.metrics,
+		tracer:             c.tracer,
+		entryPoints:        slices.Clone(c.entryPoints),
+		strictUsage:        c.strictUsage,
+		lifetimeRules:      c.lifetimeRules,
+		openGenerics:       maps.Clone(c.openGenerics),
+	}
+	if c.global != nil {
+		clone.global = &Scope{
+			Container: clone,
+			isGlobal:  true,
+			instances: make(map[string]any),
+		}
+	}
+
+	// Sites registered under several names stay shared between these names
+	clones := make(map[callSiteInterface]callSiteInterface)
+	cloneSite := func(site callSiteInterface) callSiteInterface {
+		if cloned, ok := clones[site]; ok {
+			return cloned
+		}
+		cloned := site.Clone()
+		clones[site] = cloned
+		return cloned
+	}
+	if c.callSitesRegistry != nil {
+		clone.callSitesRegistry = make(map[string]callSiteInterface, len(c.callSitesRegistry))
+		for name, site := range c.callSitesRegistry {
+			clone.callSitesRegistry[name] = cloneSite(site)
+		}
+	}
+	for _, site := range c.hostedServiceSites {
+		clone.hostedServiceSites = append(clone.hostedServiceSites, cloneSite(site))
+	}
+	for _, registration := range c.healthChecks {
+		if registration.site != nil {
+			registration.site = cloneSite(registration.site)
+		}
+		clone.healthChecks = append(clone.healthChecks, registration)
+	}
+	return clone
+}
+
+// Override replaces the registration T is resolved with by value. It must be called
+// before Build and is mainly meant for tests, e.g. on a clone of the application container.
+//
+// When T is an interface, only the interface is overridden and the implementation stays
+// registered under its other names. Otherwise every name the replaced service is registered
+// under, including its interfaces, resolves to value, and a replaced hosted service or
+// health check is replaced by value. T does not have to be registered: Override then
+// registers value like AddValue.
+//
+// Example:
+//
+//	test := app.Clone()
+//	Override[IMailer](test, &FakeMailer{})
+//	test.Build()
+func Override[T any](c *Container, value T) {
+	if c.built {
+		panic(fmt.Errorf("%w: Cannot override dependencies after Build()", ErrContainerAlreadyBuilt))
+	}
+	if c.callSitesRegistry == nil {
+		addValue(c, value)
+		return
+	}
+	t := reflect.TypeFor[T]()
+	site := newValueSite(value)
+	replaced, ok := c.lookup(registryName(t))
+	if ok && t.Kind() != reflect.Interface {
+		for name, registered := range c.registrations() {
+			if registered == replaced {
+				c.callSitesRegistry[name] = site
+			}
+		}
+		for i, hosted := range c.hostedServiceSites {
+			if hosted == replaced {
+				c.hostedServiceSites[i] = site
+			}
+		}
+		for i, registration := range c.healthChecks {
+			if registration.site == replaced {
+				c.healthChecks[i].site = site
+			}
+		}
+	}
+	for _, name := range valueNames[T]() {
+		c.callSitesRegistry[name] = site
+	}
+	c.log(slog.LevelDebug, "service overridden", serviceAttrs(site.name, Value)...)
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCloneConstructsOwnInstances(t *testing.T) {
+	base := GetContainer()
+	AddHostedService[CountingHostedService](base)
+	clone := base.Clone()
+	AddTransientWithoutInterface[D](clone)
+	base.Build()
+	clone.Build()
+
+	baseB, _ := RequireService[BInterface](base)
+	cloneB, _ := RequireService[BInterface](clone)
+	if baseB == cloneB {
+		t.Error("Expected the clone to construct its own singletons")
+	}
+	if _, err := RequireServicePtr[D](base); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected registrations of the clone not to affect the base, got %v", err)
+	}
+	if err := clone.StartAsync(context.Background()); err != nil {
+		t.Fatalf("Failed to start clone: %v", err)
+	}
+	baseHosted, _ := RequireServicePtr[CountingHostedService](base)
+	cloneHosted, _ := RequireServicePtr[CountingHostedService](clone)
+	if baseHosted.startCount != 0 || cloneHosted.startCount != 1 {
+		t.Errorf("Expected only the hosted service of the clone to start, got %d and %d", baseHosted.startCount, cloneHosted.startCount)
+	}
+}
+
+func TestCloneAfterBuildPanics(t *testing.T) {
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrContainerAlreadyBuilt) {
+			t.Errorf("Expected ErrContainerAlreadyBuilt, got %v", err)
+		}
+	}()
+	BuildContainer().Clone()
+}
+
+func TestOverrideHealthCheck(t *testing.T) {
+	c := &Container{}
+	AddHealthCheck[DatabaseHealthCheck](c, "database")
+	clone := c.Clone()
+	override := &DatabaseHealthCheck{}
+	Override(clone, override)
+	clone.Build()
+
+	if check, err := RequireServicePtr[DatabaseHealthCheck](clone); err != nil || check != override {
+		t.Errorf("Expected the override to be resolved, got %p, %v", check, err)
+	}
+	report := clone.Health().Check(context.Background())
+	if entry, ok := report.Entries["database"]; !ok || entry.Status != Degraded {
+		t.Errorf("Expected the overridden check to keep its registration, got %+v", report)
+	}
+}
+
+func TestCloneCopiesOptionsMonitor(t *testing.T) {
+	path := writeConfigFile(t, `{"rateLimit": 5}`)
+	base := &Container{}
+	if err := ConfigureMonitor[FeatureFlags](base, WatchJSONFile(path, time.Millisecond)); err != nil {
+		t.Fatalf("ConfigureMonitor failed: %v", err)
+	}
+	clone := base.Clone()
+	base.Build()
+	clone.Build()
+
+	baseMonitor, _ := RequireService[*OptionsMonitor[FeatureFlags]](base)
+	cloneMonitor, _ := RequireService[*OptionsMonitor[FeatureFlags]](clone)
+	if baseMonitor == cloneMonitor || cloneMonitor.CurrentValue().RateLimit != 5 {
+		t.Fatalf("Expected the clone to have its own monitor with the same value")
+	}
+
+	ctx := context.Background()
+	if err := clone.StartAsync(ctx); err != nil {
+		t.Fatalf("Failed to start clone: %v", err)
+	}
+	defer clone.StopAsync(ctx)
+	if baseMonitor.stop != nil || cloneMonitor.stop == nil {
+		t.Errorf("Expected only the monitor of the clone to be started")
+	}
+}
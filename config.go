@@ -0,0 +1,295 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Options holds a configuration struct bound by Configure.
+//
+// Services receive the configuration by declaring a *Options[T] parameter in their Init method:
+//
+//	func (s *Server) Init(options *Options[ServerConfig]) error {
+//		s.addr = options.Value.Addr
+//		return nil
+//	}
+type Options[T any] struct {
+	Value T
+}
+
+// ConfigSource loads configuration values into a struct.
+//
+// Sources are applied by Configure in the order they are given, so later sources
+// override values of earlier ones. Errors about a single field should be returned
+// as *ConfigError.
+type ConfigSource interface {
+	// Load binds the values of the source into target, a non-nil pointer to a struct.
+	Load(target any) error
+}
+
+// Decoder decodes the content of a configuration file. It allows file formats other
+// than JSON to be used with FileSource.
+type Decoder interface {
+	Decode(data []byte, target any) error
+}
+
+// DecoderFunc adapts a function such as json.Unmarshal to the Decoder interface.
+type DecoderFunc func(data []byte, target any) error
+
+func (f DecoderFunc) Decode(data []byte, target any) error { return f(data, target) }
+
+// ConfigError describes a configuration value that could not be bound or validated.
+type ConfigError struct {
+	Source string
+	Field  string
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("%s: field %s: %v", e.Source, e.Field, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+type fileSource struct {
+	path     string
+	decoder  Decoder
+	optional bool
+}
+
+// FileSource loads configuration from the file at path using decoder.
+func FileSource(path string, decoder Decoder) ConfigSource {
+	return &fileSource{path: path, decoder: decoder}
+}
+
+// JSONFile loads configuration from the JSON file at path.
+func JSONFile(path string) ConfigSource {
+	return FileSource(path, DecoderFunc(json.Unmarshal))
+}
+
+// OptionalJSONFile loads configuration from the JSON file at path if it exists.
+func OptionalJSONFile(path string) ConfigSource {
+	return &fileSource{path: path, decoder: DecoderFunc(json.Unmarshal), optional: true}
+}
+
+func (f *fileSource) Load(target any) error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if f.optional && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return &ConfigError{Source: f.path, Err: err}
+	}
+	if err := f.decoder.Decode(data, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &ConfigError{Source: f.path, Field: typeErr.Field, Err: err}
+		}
+		return &ConfigError{Source: f.path, Err: err}
+	}
+	return nil
+}
+
+type envSource struct {
+	prefix string
+	lookup func(string) (string, bool)
+}
+
+// EnvSource loads configuration from environment variables.
+//
+// The variable of a field is named after the field in upper snake case, prefixed with
+// prefix and an underscore, e.g. APP_MAX_CONNECTIONS for the field MaxConnections with
+// the prefix "APP". Fields of nested structs add their own segment: APP_SERVER_PORT.
+// The `env` struct tag overrides the name of a field without adding the prefix.
+//
+// Supported field kinds are strings, booleans, integers, unsigned integers, floats,
+// time.Duration and slices of those written as comma separated values.
+func EnvSource(prefix string) ConfigSource {
+	return &envSource{prefix: prefix, lookup: os.LookupEnv}
+}
+
+func (e *envSource) Load(target any) error {
+	source := "env"
+	if e.prefix != "" {
+		source = "env " + e.prefix + "_*"
+	}
+	return walkFields(reflect.ValueOf(target).Elem(), "", func(field reflect.Value, path string, tag reflect.StructTag) error {
+		name, ok := tag.Lookup("env")
+		if !ok {
+			name = envName(path)
+			if e.prefix != "" {
+				name = e.prefix + "_" + name
+			}
+		}
+		raw, ok := e.lookup(name)
+		if !ok {
+			return nil
+		}
+		if err := setFromString(field, raw); err != nil {
+			return &ConfigError{Source: source, Field: path, Err: fmt.Errorf("%s: %w", name, err)}
+		}
+		return nil
+	})
+}
+
+// applyDefaults sets every field having a `default` struct tag to the tag value.
+func applyDefaults(target reflect.Value) error {
+	return walkFields(target, "", func(field reflect.Value, path string, tag reflect.StructTag) error {
+		raw, ok := tag.Lookup("default")
+		if !ok {
+			return nil
+		}
+		if err := setFromString(field, raw); err != nil {
+			return &ConfigError{Source: "default", Field: path, Err: err}
+		}
+		return nil
+	})
+}
+
+// walkFields calls fn for every exported leaf field of the struct v. Nested structs
+// other than time.Time are walked recursively; path is the dotted Go field path.
+func walkFields(v reflect.Value, prefix string, fn func(field reflect.Value, path string, tag reflect.StructTag) error) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		path := structField.Name
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeFor[time.Time]() {
+			if err := walkFields(field, path, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, path, structField.Tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envName converts a dotted field path such as Server.MaxConnections to SERVER_MAX_CONNECTIONS.
+func envName(path string) string {
+	var b strings.Builder
+	for i, segment := range strings.Split(path, ".") {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		runes := []rune(segment)
+		for j, r := range runes {
+			if j > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[j-1]) || unicode.IsDigit(runes[j-1]) ||
+				(j+1 < len(runes) && unicode.IsLower(runes[j+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+func setFromString(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return nil
+}
+
+// Configure binds the configuration struct T and registers it as *Options[T].
+//
+// T is initialized from the `default` struct tags, then every source is applied in
+// order, so later sources override earlier ones. Finally, the bound value is checked
+// against its `validate` struct tags and, if *T implements IValidatable, its Validate method.
+//
+// Errors wrap ErrInvalidConfiguration and a *ConfigError naming the failing field and source.
+//
+// Example:
+//
+//	err := Configure[ServerConfig](c, JSONFile("config.json"), EnvSource("APP"))
+func Configure[T any](c *Container, sources ...ConfigSource) error {
+	value, err := bindConfig[T](sources)
+	if err != nil {
+		return err
+	}
+	AddValue(c, &Options[T]{Value: value})
+	return nil
+}
+
+func bindConfig[T any](sources []ConfigSource) (T, error) {
+	var value T
+	target := reflect.ValueOf(&value).Elem()
+	if target.Kind() != reflect.Struct {
+		panic(fmt.Errorf("%w: Configuration type %s should be struct type", ErrShouldBeStructType, nameForT[T]()))
+	}
+	if err := applyDefaults(target); err != nil {
+		return value, fmt.Errorf("%w for %s: %w", ErrInvalidConfiguration, nameForT[T](), err)
+	}
+	for _, source := range sources {
+		if err := source.Load(&value); err != nil {
+			return value, fmt.Errorf("%w for %s: %w", ErrInvalidConfiguration, nameForT[T](), err)
+		}
+	}
+	if err := validateValue(&value); err != nil {
+		err = &ConfigError{Source: "validation", Err: err}
+		return value, fmt.Errorf("%w for %s: %w", ErrInvalidConfiguration, nameForT[T](), err)
+	}
+	return value, nil
+}
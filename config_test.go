@@ -0,0 +1,125 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type ServerConfig struct {
+	Host           string        `default:"localhost"`
+	Port           int           `default:"8080"`
+	MaxConnections int           `json:"maxConnections"`
+	Timeout        time.Duration `default:"5s" json:"-"`
+	Tags           []string
+	Database       DatabaseConfig
+}
+
+type DatabaseConfig struct {
+	DSN string `env:"DATABASE_URL"`
+}
+
+func (s *ServerConfig) Validate() error {
+	if s.Port <= 0 {
+		return fmt.Errorf("port should be positive, got %d", s.Port)
+	}
+	return nil
+}
+
+type ConfiguredServer struct {
+	config ServerConfig
+}
+
+func (s *ConfiguredServer) Init(options *Options[ServerConfig]) error {
+	s.config = options.Value
+	return nil
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestConfigure(t *testing.T) {
+	path := writeConfigFile(t, `{"Host": "example.com", "maxConnections": 10}`)
+	t.Setenv("APP_PORT", "9090")
+	t.Setenv("APP_TAGS", "a, b")
+	t.Setenv("DATABASE_URL", "postgres://db")
+
+	c := &Container{}
+	if err := Configure[ServerConfig](c, JSONFile(path), EnvSource("APP")); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	AddSingletonWithoutInterface[ConfiguredServer](c)
+	c.Build()
+
+	server, err := RequireServicePtr[ConfiguredServer](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve server: %v", err)
+	}
+	cfg := server.config
+	if cfg.Host != "example.com" || cfg.Port != 9090 || cfg.MaxConnections != 10 || cfg.Timeout != 5*time.Second {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[1] != "b" || cfg.Database.DSN != "postgres://db" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestConfigureFieldErrors(t *testing.T) {
+	t.Setenv("APP_PORT", "not-a-number")
+	c := &Container{}
+	err := Configure[ServerConfig](c, EnvSource("APP"))
+	var configErr *ConfigError
+	if !errors.Is(err, ErrInvalidConfiguration) || !errors.As(err, &configErr) {
+		t.Fatalf("Expected ErrInvalidConfiguration with ConfigError, got %v", err)
+	}
+	if configErr.Field != "Port" || configErr.Source != "env APP_*" {
+		t.Errorf("Expected Port field from env source, got %+v", configErr)
+	}
+
+	path := writeConfigFile(t, `{"maxConnections": "many"}`)
+	err = Configure[ServerConfig](&Container{}, JSONFile(path))
+	if !errors.As(err, &configErr) || configErr.Field != "maxConnections" || configErr.Source != path {
+		t.Errorf("Expected maxConnections field from %s, got %v", path, err)
+	}
+}
+
+func TestConfigureValidation(t *testing.T) {
+	t.Setenv("APP_PORT", "-1")
+	err := Configure[ServerConfig](&Container{}, EnvSource("APP"))
+	if !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration, got %v", err)
+	}
+}
+
+func TestConfigureMissingFile(t *testing.T) {
+	err := Configure[ServerConfig](&Container{}, JSONFile(filepath.Join(t.TempDir(), "missing.json")))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist, got %v", err)
+	}
+	if err := Configure[ServerConfig](&Container{}, OptionalJSONFile(filepath.Join(t.TempDir(), "missing.json"))); err != nil {
+		t.Errorf("Optional file should be skipped, got %v", err)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	cases := map[string]string{
+		"Port":               "PORT",
+		"MaxConnections":     "MAX_CONNECTIONS",
+		"HTTPPort":           "HTTP_PORT",
+		"Server.ReadTimeout": "SERVER_READ_TIMEOUT",
+	}
+	for path, expected := range cases {
+		if got := envName(path); got != expected {
+			t.Errorf("envName(%s): expected %s, got %s", path, expected, got)
+		}
+	}
+}
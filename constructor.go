@@ -2,14 +2,14 @@ package container
 
 import "context"
 
-func (c *callSite[T]) tryFastInit(resolved *T, deps []any) (bool, error) {
+func (c *callSite[T]) tryFastInit(resolved any, deps []any) (bool, error) {
 	switch len(deps) {
 	case 0:
-		if init, ok := any(resolved).(Initializable0); ok {
+		if init, ok := resolved.(Initializable0); ok {
 			return true, init.Init()
 		}
 	case 1:
-		if init, ok := any(resolved).(Initializable1[context.Context]); ok {
+		if init, ok := resolved.(Initializable1[context.Context]); ok {
 			return true, init.Init(deps[0].(context.Context))
 		}
 	}
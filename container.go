@@ -2,10 +2,15 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type lifetime int
@@ -16,6 +21,7 @@ const (
 	Transient
 	Scoped
 	HostedService
+	TenantSingleton
 )
 
 // Container is the main dependency injection container that manages service registration,
@@ -24,13 +30,53 @@ const (
 // The container must be built using the Build method before services can be resolved.
 // Once built, no new services can be registered.
 type Container struct {
+	parent             *Container
 	callSitesRegistry  map[string]callSiteInterface
 	hostedServiceSites []callSiteInterface // HostedService callSites in registration order
 	global             *Scope
 	built              bool
-	hostedServices     []IHostedService
+	hostedServices     []*hostedServiceEntry
+	lifecycleListeners []LifecycleListener
+	lifetime           *applicationLifetime
+	supervisors        sync.WaitGroup
+	healthChecks       []healthCheckRegistration
+	health             *HealthService
+	interceptors       []ResolveInterceptor
+	logger             *slog.Logger
+	metrics            MetricsCollector
+	tracer             Tracer
+	entryPoints        []reflect.Type
+	strictUsage        bool
+	lifetimeRules      *LifetimeRules
+	graph              *dependencyGraph
+	openGenerics       map[string]openGeneric
+	scopeIDs           atomic.Uint64
+	tenantsMu          sync.Mutex
+	tenants            map[string]*tenantInstances
 }
 
+func (l lifetime) String() string {
+	switch l {
+	case Value:
+		return "Value"
+	case Singleton:
+		return "Singleton"
+	case Transient:
+		return "Transient"
+	case Scoped:
+		return "Scoped"
+	case HostedService:
+		return "HostedService"
+	case TenantSingleton:
+		return "TenantSingleton"
+	default:
+		return fmt.Sprintf("lifetime(%d)", int(l))
+	}
+}
+
+// MarshalText encodes the lifetime as its name.
+func (l lifetime) MarshalText() ([]byte, error) { return []byte(l.String()), nil }
+
 func nameForT[T any]() string {
 	t := reflect.TypeFor[T]()
 	if t.Kind() == reflect.Interface {
@@ -41,7 +87,7 @@ func nameForT[T any]() string {
 }
 func nameForPtr[T any]() string { return fmt.Sprintf("%T", new(T)) }
 
-func addI[I any, T any](c *Container, lifetime lifetime) {
+func addI[I any, T any](c *Container, lifetime lifetime, opts ...Option) {
 	if c.built {
 		panic(fmt.Errorf("%w: Cannot add dependencies after Build()", ErrContainerAlreadyBuilt))
 	}
@@ -75,10 +121,11 @@ func addI[I any, T any](c *Container, lifetime lifetime) {
 		panic(fmt.Errorf("%w: Dependency %s already exists in container", ErrTypeAlreadyRegistered, nameI))
 	}
 
-	callSite := add[T](c, lifetime)
+	callSite := add[T](c, lifetime, opts...)
 	c.callSitesRegistry[nameI] = callSite
+	c.log(slog.LevelDebug, "service registered as interface", slog.String("service", nameT), slog.String("interface", nameI))
 }
-func add[T any](c *Container, lifetime lifetime) *callSite[T] {
+func add[T any](c *Container, lifetime lifetime, opts ...Option) *callSite[T] {
 	if c.built {
 		panic(fmt.Errorf("%w: Cannot add dependencies after Build()", ErrContainerAlreadyBuilt))
 	}
@@ -99,34 +146,26 @@ func add[T any](c *Container, lifetime lifetime) *callSite[T] {
 	if kind != reflect.Struct {
 		panic(fmt.Errorf("%w: Type %s should be struct type", ErrShouldBeStructType, depNameType))
 	}
-	initFunc, ok := reflect.PointerTo(depType).MethodByName("Init")
-	if !ok {
-		panic(fmt.Errorf("%w: Init method not found for %s dependency", ErrShouldImplementInitMethod, depNameType))
-	}
-	// Validate Init signature: must return exactly one error value
-	if initFunc.Type.NumOut() != 1 {
-		panic(fmt.Errorf("%w: Init method for %s must return exactly one value (error), got %d", ErrShouldImplementInitMethod, depNameType, initFunc.Type.NumOut()))
-	}
-	errorType := reflect.TypeOf((*error)(nil)).Elem()
-	if !initFunc.Type.Out(0).Implements(errorType) {
-		panic(fmt.Errorf("%w: Init method for %s must return error, got %s", ErrShouldImplementInitMethod, depNameType, initFunc.Type.Out(0)))
+	initFunc, err := initMethodOf(depType, depNameType)
+	if err != nil {
+		panic(err)
 	}
 	dependencies := []string{}
 	for i := range initFunc.Type.NumIn() {
 		if i == 0 {
 			continue // for any method zero argument would be "this" argument
 		}
-		arg := initFunc.Type.In(i)
-		name := arg.String()
-		if arg.Kind() == reflect.Struct || arg.Kind() == reflect.Ptr || arg.Kind() == reflect.Interface {
-			if slices.Contains(dependencies, name) {
-				panic("Dependency " + name + " already exists for " + depNameType)
-			}
-			dependencies = append(dependencies, name)
+		// Parameters of any kind are dependencies. Structs, pointers and interfaces are
+		// usually services, other kinds (ints, strings, slices, maps, funcs, ...) must be
+		// registered with AddValue, preferably as named types such as `type DSN string`.
+		name := initFunc.Type.In(i).String()
+		if slices.Contains(dependencies, name) {
+			panic("Dependency " + name + " already exists for " + depNameType)
 		}
+		dependencies = append(dependencies, name)
 	}
 
-	_, ok = c.callSitesRegistry[depNameType]
+	_, ok := c.callSitesRegistry[depNameType]
 	if ok {
 		panic("Dependency " + depNameType + " already exists in container")
 	}
@@ -136,6 +175,8 @@ func add[T any](c *Container, lifetime lifetime) *callSite[T] {
 		dependencyNames: dependencies,
 		dependencies:    nil,
 		initMethod:      initFunc,
+		options:         newServiceOptions(opts),
+		validate:        needsValidation(depType),
 		instance:        nil,
 	}
 	c.callSitesRegistry[depNameType] = callSite
@@ -144,10 +185,29 @@ func add[T any](c *Container, lifetime lifetime) *callSite[T] {
 	if lifetime == HostedService {
 		c.hostedServiceSites = append(c.hostedServiceSites, callSite)
 	}
+	c.log(slog.LevelDebug, "service registered", append(serviceAttrs(depNameType, lifetime),
+		slog.Any("dependencies", dependencies))...)
 
 	return callSite
 }
 
+// initMethodOf returns the Init method of *t and checks that it returns a single error.
+func initMethodOf(t reflect.Type, name string) (reflect.Method, error) {
+	initFunc, ok := reflect.PointerTo(t).MethodByName("Init")
+	if !ok {
+		return initFunc, fmt.Errorf("%w: Init method not found for %s dependency", ErrShouldImplementInitMethod, name)
+	}
+	// Validate Init signature: must return exactly one error value
+	if initFunc.Type.NumOut() != 1 {
+		return initFunc, fmt.Errorf("%w: Init method for %s must return exactly one value (error), got %d", ErrShouldImplementInitMethod, name, initFunc.Type.NumOut())
+	}
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if !initFunc.Type.Out(0).Implements(errorType) {
+		return initFunc, fmt.Errorf("%w: Init method for %s must return error, got %s", ErrShouldImplementInitMethod, name, initFunc.Type.Out(0))
+	}
+	return initFunc, nil
+}
+
 // CreateScope creates a new dependency injection scope for scoped service resolution.
 //
 // The returned scope should be used to resolve scoped services using [RequireServiceFor]
@@ -156,33 +216,13 @@ func (c *Container) CreateScope() *Scope {
 	if !c.built {
 		panic(fmt.Errorf("%w: You should call Build() before CreateScope()", ErrContainerNotBuilt))
 	}
-	return &Scope{
+	scope := &Scope{
 		Container: c,
+		id:        c.scopeIDs.Add(1),
 		instances: make(map[string]any),
 	}
-}
-
-func (c *Container) checkCircle(typeName string, visited []string) error {
-	source, ok := c.callSitesRegistry[typeName]
-	if !ok {
-		return fmt.Errorf("%w: %s", ErrDependencyNotFound, typeName)
-	}
-	if visited == nil {
-		visited = make([]string, 0)
-	}
-	visited = append(visited, typeName)
-	for _, dep := range source.Deps() {
-		dep = strings.TrimPrefix(dep, "*")
-		if slices.Contains(visited, dep) {
-			circle := strings.Join(visited, " -> ")
-			return fmt.Errorf("%w: %s", ErrCircleDependency, circle)
-		}
-		err := c.checkCircle(dep, visited)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	c.log(slog.LevelDebug, "scope created", slog.Uint64("scope", scope.id))
+	return scope
 }
 
 // Build validates the dependency graph and prepares the container for service resolution.
@@ -193,58 +233,90 @@ func (c *Container) checkCircle(typeName string, visited []string) error {
 //   - Validates that all dependencies can be resolved
 //   - Detects and reports circular dependencies
 //   - Builds call sites for efficient service creation
+//   - Registers IHostApplicationLifetime and *HealthService unless already registered
 //   - Marks the container as built (no more registrations allowed)
 func (c *Container) Build() {
 	defer func() { c.built = true }()
-	if c.callSitesRegistry == nil {
-		return
+	defer func() {
+		if r := recover(); r != nil {
+			c.log(slog.LevelError, "container build failed", slog.Any("error", r))
+			panic(r)
+		}
+	}()
+	begin := time.Now()
+	if _, ok := c.callSitesRegistry[nameForT[IHostApplicationLifetime]()]; !ok {
+		AddValue[IHostApplicationLifetime](c, c.appLifetime())
+	}
+	if _, ok := c.callSitesRegistry[nameForT[*HealthService]()]; !ok {
+		AddValue(c, c.Health())
 	}
 
-	for typeName, site := range c.callSitesRegistry {
-		err := c.checkCircle(typeName, nil)
+	if err := c.materializeOpenGenerics(); err != nil {
+		panic(err)
+	}
+	for _, site := range c.callSitesRegistry {
+		err := site.BuildCallSite(c)
 		if err != nil {
-			err = fmt.Errorf("in %s found: %w", typeName, err)
 			panic(err)
 		}
-		err = site.BuildCallSite(c)
-		if err != nil {
-			panic(err)
+	}
+	if err := c.checkLifetimes(); err != nil {
+		panic(err)
+	}
+	graph := c.newDependencyGraph()
+	if err := graph.cycleError(); err != nil {
+		panic(err)
+	}
+	c.graph = graph
+	if c.strictUsage {
+		if report := c.AnalyzeUsage(); !report.Empty() {
+			panic(fmt.Errorf("%w: %s", ErrUnusedRegistration, report))
 		}
 	}
 
 	for _, site := range c.hostedServiceSites {
-		instance, err := site.Build(c.global)
+		instance, err := site.Build(context.Background(), c.global)
 		if err != nil {
 			panic(fmt.Errorf("failed to build hosted service %s: %w", site.Name(), err))
 		}
 		if hostedSvc, ok := instance.(IHostedService); ok {
-			c.hostedServices = append(c.hostedServices, hostedSvc)
+			c.hostedServices = append(c.hostedServices, &hostedServiceEntry{
+				site:    site,
+				service: hostedSvc,
+			})
 		}
 	}
+	c.log(slog.LevelDebug, "container built",
+		slog.Int("registrations", len(c.callSitesRegistry)),
+		slog.Int("hostedServices", len(c.hostedServices)),
+		slog.Duration("duration", time.Since(begin)))
 }
 
 // AddHostedService registers a hosted service with the container.
 //
 // HostedService must implement IHostedService interface with Start/Stop methods.
 // Services are started in registration order and stopped in reverse order.
-func AddHostedService[T any](c *Container) { add[T](c, HostedService) }
+// Options such as WithStartTimeout, WithStopTimeout and WithRestartPolicy configure
+// how the service is started, stopped and supervised.
+func AddHostedService[T any](c *Container, opts ...Option) { add[T](c, HostedService, opts...) }
 
 // AddTransientWithoutInterface registers a transient service without an interface mapping.
 //
 // Transient services are created each time they're requested from the service container.
-func AddTransientWithoutInterface[T any](c *Container) { add[T](c, Transient) }
+func AddTransientWithoutInterface[T any](c *Container, opts ...Option) { add[T](c, Transient, opts...) }
 
 // AddSingletonWithoutInterface registers a singleton service without an interface mapping.
 //
 // Singleton services are created the first time they're requested and the same instance
-// is reused for all subsequent requests.
-func AddSingletonWithoutInterface[T any](c *Container) { add[T](c, Singleton) }
+// is reused for all subsequent requests. Use WithConstructionRetry to configure what
+// happens when the construction fails.
+func AddSingletonWithoutInterface[T any](c *Container, opts ...Option) { add[T](c, Singleton, opts...) }
 
 // AddScopedWithoutInterface registers a scoped service without an interface mapping.
 //
 // Scoped services are created once per client request (scope). Within the same scope,
 // the same instance is returned for all requests.
-func AddScopedWithoutInterface[T any](c *Container) { add[T](c, Scoped) }
+func AddScopedWithoutInterface[T any](c *Container, opts ...Option) { add[T](c, Scoped, opts...) }
 
 // AddTransient registers a transient service with interface mapping.
 //
@@ -253,17 +325,18 @@ func AddScopedWithoutInterface[T any](c *Container) { add[T](c, Scoped) }
 // Type parameters:
 //   - I: The interface type that will be used for service resolution
 //   - T: The concrete implementation type that implements interface I
-func AddTransient[I any, T any](c *Container) { addI[I, T](c, Transient) }
+func AddTransient[I any, T any](c *Container, opts ...Option) { addI[I, T](c, Transient, opts...) }
 
 // AddSingleton registers a singleton service with interface mapping.
 //
 // Singleton services are created the first time they're requested and the same instance
-// is reused for all subsequent requests.
+// is reused for all subsequent requests. Use WithConstructionRetry to configure what
+// happens when the construction fails.
 //
 // Type parameters:
 //   - I: The interface type that will be used for service resolution
 //   - T: The concrete implementation type that implements interface I
-func AddSingleton[I any, T any](c *Container) { addI[I, T](c, Singleton) }
+func AddSingleton[I any, T any](c *Container, opts ...Option) { addI[I, T](c, Singleton, opts...) }
 
 // AddScoped registers a scoped service with interface mapping.
 //
@@ -272,7 +345,7 @@ func AddSingleton[I any, T any](c *Container) { addI[I, T](c, Singleton) }
 // Type parameters:
 //   - I: The interface type that will be used for service resolution
 //   - T: The concrete implementation type that implements interface I
-func AddScoped[I any, T any](c *Container) { addI[I, T](c, Scoped) }
+func AddScoped[I any, T any](c *Container, opts ...Option) { addI[I, T](c, Scoped, opts...) }
 
 // AddValue registers an existing value as a singleton service.
 //
@@ -286,13 +359,14 @@ func AddScoped[I any, T any](c *Container) { addI[I, T](c, Scoped) }
 //
 //	ctx := context.Background()
 //	AddValue[context.Context](container, ctx)
-func AddValue[T any](c *Container, value T) {
+func AddValue[T any](c *Container, value T) { addValue(c, value) }
+
+func addValue[T any](c *Container, value T) *callSite[T] {
 	if c.built {
 		panic(fmt.Errorf("%w: Cannot add dependencies after Build()", ErrContainerAlreadyBuilt))
 	}
 
 	depNameType := nameForT[T]()
-	depPtrNameType := nameForPtr[T]()
 
 	if c.callSitesRegistry == nil {
 		c.callSitesRegistry = make(map[string]callSiteInterface)
@@ -310,30 +384,39 @@ func AddValue[T any](c *Container, value T) {
 		panic(fmt.Errorf("%w: Dependency %s already exists in container", ErrTypeAlreadyRegistered, depNameType))
 	}
 
+	callSite := newValueSite(value)
+	for _, name := range valueNames[T]() {
+		c.callSitesRegistry[name] = callSite
+	}
+	c.log(slog.LevelDebug, "service registered", serviceAttrs(depNameType, Value)...)
+	return callSite
+}
+
+func newValueSite[T any](value T) *callSite[T] {
 	instance := new(T)
 	*instance = value
 
 	callSite := &callSite[T]{
-		name:            depNameType,
+		name:            nameForT[T](),
 		lifetime:        Value,
 		dependencyNames: []string{},
 		dependencies:    nil,
 		initMethod:      reflect.Method{},
 		instance:        nil,
 	}
+	callSite.instance = instance
+	callSite.constructed.Store(true)
+	return callSite
+}
 
-	callSite.once.Do(func() {
-		callSite.instance = instance
-		callSite.constructorError = nil
-	})
-
-	c.callSitesRegistry[depNameType] = callSite
-	c.callSitesRegistry[depPtrNameType] = callSite
-
+// valueNames returns the names a value of type T is registered under.
+func valueNames[T any]() []string {
+	depNameType := nameForT[T]()
+	names := []string{depNameType, nameForPtr[T]()}
 	if strings.HasPrefix(depNameType, "*") {
-		depNameWithoutPtr := strings.TrimPrefix(depNameType, "*")
-		c.callSitesRegistry[depNameWithoutPtr] = callSite
+		names = append(names, strings.TrimPrefix(depNameType, "*"))
 	}
+	return names
 }
 
 // RequireServicePtr resolves a service instance from the container's global scope.
@@ -358,6 +441,7 @@ func RequireService[T any](c *Container) (T, error) {
 //
 // Services are started in registration order. If any service fails to start,
 // the error is returned immediately and remaining services are not started.
+// When all services have started, ApplicationStarted of the container lifetime is closed.
 //
 // The provided context can be used for cancellation and timeouts.
 func (c *Container) StartAsync(ctx context.Context) error {
@@ -365,30 +449,82 @@ func (c *Container) StartAsync(ctx context.Context) error {
 		return fmt.Errorf("%w: You should call Build() before StartAsync()", ErrContainerNotBuilt)
 	}
 
-	for i, svc := range c.hostedServices {
-		if err := svc.Start(ctx); err != nil {
+	for i := range c.hostedServices {
+		if err := c.startHostedService(ctx, i); err != nil {
 			return fmt.Errorf("failed to start hosted service #%d: %w", i, err)
 		}
+		c.supervise(i)
 	}
+	c.appLifetime().notifyStarted()
+	return nil
+}
+
+// StartParallelAsync starts all registered HostedService instances concurrently in
+// dependency waves.
+//
+// A hosted service that depends (directly or through other services) on another
+// hosted service is started only after that service has started. Services whose
+// dependencies have all started form a wave and are started in parallel, with at most
+// maxConcurrency Start calls running at once. A maxConcurrency of zero or less means
+// no limit.
+//
+// If any service in a wave fails to start, the remaining services of that wave are
+// still awaited, later waves are not started, and all errors of the wave are returned
+// joined in registration order. When ctx is done, services that were not started yet
+// are not started and the error of ctx is returned.
+func (c *Container) StartParallelAsync(ctx context.Context, maxConcurrency int) error {
+	if !c.built {
+		return fmt.Errorf("%w: You should call Build() before StartParallelAsync()", ErrContainerNotBuilt)
+	}
+
+	for _, wave := range c.hostedServiceWaves() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := runParallel(ctx, wave, maxConcurrency, func(ctx context.Context, i int) error {
+			if err := c.startHostedService(ctx, i); err != nil {
+				return fmt.Errorf("failed to start hosted service #%d %s: %w", i, c.hostedServices[i].site.Name(), err)
+			}
+			c.supervise(i)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	c.appLifetime().notifyStarted()
 	return nil
 }
 
 // StopAsync stops all registered HostedService instances.
 //
 // Services are stopped in reverse registration order (LIFO).
-// All services are stopped regardless of errors, and the first error is returned.
+// All services are stopped regardless of errors, and their errors are returned joined
+// in stop order, so every service that failed or timed out is reported.
+// ApplicationStopping of the container lifetime is closed before the first service
+// is stopped and ApplicationStopped after the last one. Supervision of services with
+// a RestartPolicy ends before any service is stopped.
+//
+// Services are stopped only once: later calls, including the one started by
+// IHostApplicationLifetime.StopApplication, wait for the first one and return its result.
 //
 // The provided context typically includes a timeout for graceful shutdown.
 func (c *Container) StopAsync(ctx context.Context) error {
 	if !c.built {
 		return fmt.Errorf("%w: You should call Build() before StopAsync()", ErrContainerNotBuilt)
 	}
+	return c.appLifetime().stop(ctx)
+}
+
+func (c *Container) stopHostedServices(ctx context.Context) error {
+	c.appLifetime().notifyStopping()
+	c.supervisors.Wait()
 
-	var firstErr error
+	var errs []error
 	for i := len(c.hostedServices) - 1; i >= 0; i-- {
-		if err := c.hostedServices[i].Stop(ctx); err != nil && firstErr == nil {
-			firstErr = err
+		if err := c.stopHostedService(ctx, i); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop hosted service #%d %s: %w", i, c.hostedServices[i].site.Name(), err))
 		}
 	}
-	return firstErr
+	return errors.Join(errs...)
 }
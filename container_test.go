@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -172,6 +173,9 @@ func TestNameFor(t *testing.T) {
 	if nameI != "*container.CounterInterface" {
 		t.Errorf("%s is not equal to container.CounterInterface", nameI)
 	}
+	if name4 != "context.Context" || nameI5 != "*context.Context" {
+		t.Errorf("%s and %s is not equal to context.Context and *context.Context", name4, nameI5)
+	}
 }
 
 func TestActivator(t *testing.T) {
@@ -812,3 +816,83 @@ func TestWithContextDependency(t *testing.T) {
 		t.Errorf("Expected context value 'context-in-dependency', got %v", value)
 	}
 }
+
+type ValueConsumer[V any] struct{ Value V }
+
+func (v *ValueConsumer[V]) Init(value V) error {
+	v.Value = value
+	return nil
+}
+
+type DSN string
+
+func testValueDependency[V any](t *testing.T, value V, equal func(a, b V) bool) {
+	t.Run(reflect.TypeFor[V]().String(), func(t *testing.T) {
+		c := &Container{}
+		AddValue(c, value)
+		AddTransientWithoutInterface[ValueConsumer[V]](c)
+		c.Build()
+
+		consumer, err := RequireServicePtr[ValueConsumer[V]](c)
+		if err != nil {
+			t.Fatalf("Failed to resolve consumer: %v", err)
+		}
+		if !equal(consumer.Value, value) {
+			t.Errorf("Expected %v, got %v", value, consumer.Value)
+		}
+	})
+}
+
+func deepEqual[V any](a, b V) bool { return reflect.DeepEqual(a, b) }
+
+func TestValueKindDependencies(t *testing.T) {
+	ch := make(chan int)
+	testValueDependency(t, true, deepEqual)
+	testValueDependency(t, 42, deepEqual)
+	testValueDependency(t, int64(-7), deepEqual)
+	testValueDependency(t, uint8(255), deepEqual)
+	testValueDependency(t, uintptr(1), deepEqual)
+	testValueDependency(t, 3.14, deepEqual)
+	testValueDependency(t, complex(1, 2), deepEqual)
+	testValueDependency(t, "postgres://localhost", deepEqual)
+	testValueDependency(t, DSN("postgres://localhost"), deepEqual)
+	testValueDependency(t, []string{"a", "b"}, deepEqual)
+	testValueDependency(t, [2]int{1, 2}, deepEqual)
+	testValueDependency(t, map[string]int{"a": 1}, deepEqual)
+	testValueDependency(t, ch, func(a, b chan int) bool { return a == b })
+	testValueDependency(t, func() string { return "called" }, func(a, b func() string) bool {
+		return a != nil && a() == b()
+	})
+	testValueDependency[error](t, nil, func(a, b error) bool { return a == nil })
+	testValueDependency[fmt.Stringer](t, &A{Str: "stringer"}, func(a, b fmt.Stringer) bool {
+		return a.String() == b.String()
+	})
+}
+
+func TestValueKindDependencyNotRegistered(t *testing.T) {
+	kinds := map[string]func(c *Container){
+		"int":    func(c *Container) { AddTransientWithoutInterface[ValueConsumer[int]](c) },
+		"string": func(c *Container) { AddTransientWithoutInterface[ValueConsumer[string]](c) },
+		"slice":  func(c *Container) { AddTransientWithoutInterface[ValueConsumer[[]byte]](c) },
+		"map":    func(c *Container) { AddTransientWithoutInterface[ValueConsumer[map[string]string]](c) },
+		"func":   func(c *Container) { AddTransientWithoutInterface[ValueConsumer[func()]](c) },
+		"chan":   func(c *Container) { AddTransientWithoutInterface[ValueConsumer[chan struct{}]](c) },
+	}
+	for kind, register := range kinds {
+		t.Run(kind, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatalf("The code did not panic")
+				}
+				err := r.(error)
+				if !errors.Is(err, ErrDependencyNotFound) || !strings.Contains(err.Error(), "AddValue") {
+					t.Errorf("Expected ErrDependencyNotFound mentioning AddValue, got %v", err)
+				}
+			}()
+			c := &Container{}
+			register(c)
+			c.Build()
+		})
+	}
+}
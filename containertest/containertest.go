@@ -0,0 +1,134 @@
+// Package containertest provides helpers to test services wired by a container.
+//
+// A test clones the configured, unbuilt application container, overrides the
+// registrations it wants to replace with fakes and resolves services. Wiring errors
+// fail the test, and scopes and hosted services are closed and stopped when the
+// test ends.
+//
+// Example:
+//
+//	func TestSignup(t *testing.T) {
+//		c := containertest.New(t, app.NewContainer())
+//		mailer := &FakeMailer{}
+//		containertest.Override[IMailer](c, mailer)
+//
+//		users := containertest.Require[IUserService](c)
+//		...
+//	}
+package containertest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	container "github.com/kondr1/tiny-di"
+)
+
+// Container is a clone of a container owned by a single test.
+//
+// It is built automatically by the first call to Require, CreateScope or Start.
+type Container struct {
+	*container.Container
+
+	tb    testing.TB
+	built bool
+}
+
+// New clones base, which must not be built, for the test tb.
+func New(tb testing.TB, base *container.Container) *Container {
+	tb.Helper()
+	var clone *container.Container
+	if err := catch(func() { clone = base.Clone() }); err != nil {
+		tb.Fatalf("containertest: failed to clone container: %v", err)
+	}
+	return &Container{Container: clone, tb: tb}
+}
+
+// Override replaces the registration T is resolved with by value, including
+// registrations of interfaces. See container.Override for the details.
+func Override[T any](c *Container, value T) {
+	c.tb.Helper()
+	if c.built {
+		c.tb.Fatalf("containertest: cannot override %s after the container was built", reflect.TypeFor[T]())
+	}
+	container.Override(c.Container, value)
+}
+
+// Build builds the container and fails the test on wiring errors.
+// Calling Build again does nothing.
+func (c *Container) Build() {
+	c.tb.Helper()
+	if c.built {
+		return
+	}
+	c.built = true
+	if err := catch(c.Container.Build); err != nil {
+		c.tb.Fatalf("containertest: failed to build container: %v", err)
+	}
+}
+
+// Require resolves T from the global scope of the container and fails the test on error.
+func Require[T any](c *Container) T {
+	c.tb.Helper()
+	c.Build()
+	service, err := container.RequireService[T](c.Container)
+	if err != nil {
+		c.tb.Fatalf("containertest: failed to resolve %s: %v", reflect.TypeFor[T](), err)
+	}
+	return service
+}
+
+// RequireForScope resolves T from scope and fails the test on error.
+func RequireForScope[T any](c *Container, scope *container.Scope) T {
+	c.tb.Helper()
+	service, err := container.RequireServiceForScope[T](scope)
+	if err != nil {
+		c.tb.Fatalf("containertest: failed to resolve %s in scope %d: %v", reflect.TypeFor[T](), scope.ID(), err)
+	}
+	return service
+}
+
+// CreateScope creates a scope that is closed when the test ends.
+func (c *Container) CreateScope() *container.Scope {
+	c.tb.Helper()
+	c.Build()
+	scope := c.Container.CreateScope()
+	c.tb.Cleanup(func() {
+		if err := scope.Close(); err != nil {
+			c.tb.Errorf("containertest: failed to close scope %d: %v", scope.ID(), err)
+		}
+	})
+	return scope
+}
+
+// Start starts the hosted services of the container and fails the test on error.
+// The services are stopped when the test ends.
+func (c *Container) Start() {
+	c.tb.Helper()
+	c.Build()
+	if err := c.StartAsync(context.Background()); err != nil {
+		c.tb.Fatalf("containertest: failed to start hosted services: %v", err)
+	}
+	c.tb.Cleanup(func() {
+		if err := c.StopAsync(context.Background()); err != nil {
+			c.tb.Errorf("containertest: failed to stop hosted services: %v", err)
+		}
+	})
+}
+
+// catch returns the panic of fn as an error.
+func catch(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
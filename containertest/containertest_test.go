@@ -0,0 +1,169 @@
+package containertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	container "github.com/kondr1/tiny-di"
+)
+
+type Mailer interface{ Send(to string) error }
+
+type SMTPMailer struct{ Sent []string }
+
+func (m *SMTPMailer) Init() error { return nil }
+func (m *SMTPMailer) Send(to string) error {
+	return errors.New("no network in tests")
+}
+
+type FakeMailer struct{ Sent []string }
+
+func (m *FakeMailer) Send(to string) error {
+	m.Sent = append(m.Sent, to)
+	return nil
+}
+
+type Signup struct{ mailer *SMTPMailer }
+
+func (s *Signup) Init(mailer *SMTPMailer) error {
+	s.mailer = mailer
+	return nil
+}
+
+type Worker struct{ Running bool }
+
+func (w *Worker) Init() error                     { return nil }
+func (w *Worker) Start(ctx context.Context) error { w.Running = true; return nil }
+func (w *Worker) Stop(ctx context.Context) error  { w.Running = false; return nil }
+
+type Session struct{ Closed bool }
+
+func (s *Session) Init() error  { return nil }
+func (s *Session) Close() error { s.Closed = true; return nil }
+
+func newBase() *container.Container {
+	c := &container.Container{}
+	container.AddSingleton[Mailer, SMTPMailer](c)
+	container.AddTransientWithoutInterface[Signup](c)
+	container.AddHostedService[Worker](c)
+	container.AddScopedWithoutInterface[Session](c)
+	return c
+}
+
+// fakeTB records failures and cleanups instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	failures []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper() {}
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+	runtime.Goexit()
+}
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) cleanup() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+// run calls fn in its own goroutine so that Fatalf can stop it.
+func run(fn func(tb testing.TB)) *fakeTB {
+	tb := &fakeTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+	return tb
+}
+
+func TestOverrideInterfaceAlias(t *testing.T) {
+	base := newBase()
+	c := New(t, base)
+	fake := &FakeMailer{}
+	Override[Mailer](c, fake)
+
+	mailer := Require[Mailer](c)
+	if err := mailer.Send("user@example.com"); err != nil || len(fake.Sent) != 1 {
+		t.Errorf("Expected the fake mailer, got %T: %v", mailer, err)
+	}
+	// The implementation stays registered under its own type
+	if signup := Require[*Signup](c); signup.mailer == nil {
+		t.Error("Expected SMTPMailer to be injected")
+	}
+
+	// The base container is not affected and can still be built
+	base.Build()
+	if mailer, err := container.RequireService[Mailer](base); err != nil || mailer == Mailer(fake) {
+		t.Errorf("Expected the base container to keep SMTPMailer, got %v, %v", mailer, err)
+	}
+}
+
+func TestOverrideImplementation(t *testing.T) {
+	c := New(t, newBase())
+	fake := &SMTPMailer{Sent: []string{"fake"}}
+	Override(c, fake)
+
+	if signup := Require[*Signup](c); signup.mailer != fake {
+		t.Errorf("Expected the overridden mailer to be injected, got %+v", signup.mailer)
+	}
+	if mailer := Require[Mailer](c); mailer != Mailer(fake) {
+		t.Errorf("Expected the interface to resolve to the override, got %+v", mailer)
+	}
+}
+
+func TestCleanupStopsHostedServicesAndClosesScopes(t *testing.T) {
+	var worker *Worker
+	var session *Session
+	tb := run(func(tb testing.TB) {
+		c := New(tb, newBase())
+		c.Start()
+		worker = Require[*Worker](c)
+		session = RequireForScope[*Session](c, c.CreateScope())
+	})
+	if len(tb.failures) > 0 {
+		t.Fatalf("Unexpected failures %v", tb.failures)
+	}
+	if !worker.Running || session.Closed {
+		t.Fatalf("Expected running worker and open session before cleanup")
+	}
+
+	tb.cleanup()
+	if worker.Running || !session.Closed {
+		t.Errorf("Expected cleanup to stop the worker and close the session, got %+v %+v", worker, session)
+	}
+}
+
+func TestWiringErrorsFailTheTest(t *testing.T) {
+	tb := run(func(tb testing.TB) {
+		base := &container.Container{}
+		container.AddTransientWithoutInterface[Signup](base)
+		c := New(tb, base)
+		Require[*Signup](c)
+		t.Error("Expected Require to stop the test")
+	})
+	if len(tb.failures) != 1 || !strings.Contains(tb.failures[0], "failed to build container") {
+		t.Errorf("Expected a build failure, got %v", tb.failures)
+	}
+
+	tb = run(func(tb testing.TB) {
+		c := New(tb, newBase())
+		c.Build()
+		Override[Mailer](c, &FakeMailer{})
+	})
+	if len(tb.failures) != 1 || !strings.Contains(tb.failures[0], "after the container was built") {
+		t.Errorf("Expected an override failure, got %v", tb.failures)
+	}
+}
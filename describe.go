@@ -0,0 +1,110 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// ServiceDescriptor describes a registered service.
+type ServiceDescriptor struct {
+	// Type is the name the service is registered with.
+	Type string `json:"type"`
+	// Interfaces are the interfaces the service is also registered under.
+	Interfaces []string `json:"interfaces,omitempty"`
+	Lifetime   lifetime `json:"lifetime"`
+	// Dependencies are the types of the Init parameters of the service.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Dependents are the services with an Init parameter resolved to this service.
+	Dependents []string `json:"dependents,omitempty"`
+	// Built reports whether an instance of the service was constructed successfully.
+	// Values are always built.
+	Built bool `json:"built"`
+	// Constructions is the number of successful constructions.
+	Constructions uint64 `json:"constructions"`
+	// Inherited reports whether the service is registered in a parent container, see NewChild.
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// ServiceDescriptors are the descriptors of the services of a container, ordered by type.
+type ServiceDescriptors []ServiceDescriptor
+
+// Describe returns a descriptor of every registered service, ordered by type.
+//
+// Dependencies are matched to dependents only after Build. The result can be encoded
+// as JSON or rendered as a table with ServiceDescriptors.WriteTable.
+func (c *Container) Describe() ServiceDescriptors {
+	sites := c.uniqueSites()
+	descriptors := make(ServiceDescriptors, len(sites))
+	indexByName := make(map[string]int, len(sites))
+	for i, site := range sites {
+		indexByName[site.Name()] = i
+		descriptors[i] = ServiceDescriptor{
+			Type:          site.Name(),
+			Lifetime:      site.Lifetime(),
+			Dependencies:  slices.Clone(site.Deps()),
+			Built:         site.Lifetime() == Value || site.Constructions() > 0,
+			Constructions: site.Constructions(),
+			Inherited:     !c.owns(site),
+		}
+	}
+
+	for key, site := range c.registrations() {
+		name := site.Name()
+		if key == name || key == "*"+name || key == strings.TrimPrefix(name, "*") {
+			continue
+		}
+		d := &descriptors[indexByName[name]]
+		d.Interfaces = append(d.Interfaces, strings.TrimPrefix(key, "*"))
+	}
+
+	for _, site := range sites {
+		for _, depName := range site.Deps() {
+			dep, ok := c.lookupFor(site, depName)
+			if !ok {
+				continue
+			}
+			i, ok := indexByName[dep.Name()]
+			if !ok || sites[i] != dep {
+				continue // shadowed by a child container
+			}
+			d := &descriptors[i]
+			if !slices.Contains(d.Dependents, site.Name()) {
+				d.Dependents = append(d.Dependents, site.Name())
+			}
+		}
+	}
+	for i := range descriptors {
+		slices.Sort(descriptors[i].Interfaces)
+		slices.Sort(descriptors[i].Dependents)
+	}
+	return descriptors
+}
+
+// WriteTable renders the descriptors as a text table with one row per service.
+func (ds ServiceDescriptors) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tLIFETIME\tINTERFACES\tDEPENDENCIES\tDEPENDENTS\tBUILT\tCONSTRUCTIONS")
+	for _, d := range ds {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%d\n",
+			d.Type, d.Lifetime, listOrDash(d.Interfaces), listOrDash(d.Dependencies), listOrDash(d.Dependents),
+			d.Built, d.Constructions)
+	}
+	return tw.Flush()
+}
+
+// String renders the descriptors as a text table.
+func (ds ServiceDescriptors) String() string {
+	var b strings.Builder
+	_ = ds.WriteTable(&b)
+	return b.String()
+}
+
+func listOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,65 @@
+package container
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	c := BuildContainer()
+	if _, err := RequireService[BInterface](c); err != nil {
+		t.Fatalf("Failed to resolve B: %v", err)
+	}
+
+	descriptors := c.Describe()
+	byType := make(map[string]ServiceDescriptor)
+	for _, d := range descriptors {
+		byType[d.Type] = d
+	}
+
+	a := byType["container.A"]
+	if a.Lifetime != Transient || !slices.Equal(a.Interfaces, []string{"container.AInterface"}) {
+		t.Errorf("Unexpected descriptor for A: %+v", a)
+	}
+	if !slices.Equal(a.Dependencies, []string{"*container.Counter"}) || !slices.Equal(a.Dependents, []string{"container.B", "container.C"}) {
+		t.Errorf("Unexpected dependencies of A: %+v", a)
+	}
+	if !a.Built || a.Constructions != 1 {
+		t.Errorf("Expected A to be constructed once, got %+v", a)
+	}
+	if counter := byType["container.Counter"]; counter.Constructions != 1 || len(counter.Dependents) != 3 {
+		t.Errorf("Unexpected descriptor for Counter: %+v", counter)
+	}
+	if cs := byType["container.C"]; cs.Built || cs.Lifetime != Scoped {
+		t.Errorf("Expected C not to be built, got %+v", cs)
+	}
+	if !slices.IsSortedFunc(descriptors, func(a, b ServiceDescriptor) int { return strings.Compare(a.Type, b.Type) }) {
+		t.Errorf("Expected descriptors sorted by type")
+	}
+}
+
+func TestDescribeRendering(t *testing.T) {
+	c := BuildContainer()
+
+	table := c.Describe().String()
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	if !strings.HasPrefix(lines[0], "TYPE") || !strings.Contains(lines[0], "CONSTRUCTIONS") {
+		t.Errorf("Unexpected header %q", lines[0])
+	}
+	if len(lines) != len(c.Describe())+1 {
+		t.Errorf("Expected one row per service, got:\n%s", table)
+	}
+	if !strings.Contains(table, "container.AInterface") {
+		t.Errorf("Expected interfaces in table:\n%s", table)
+	}
+
+	data, err := json.Marshal(c.Describe())
+	if err != nil {
+		t.Fatalf("Failed to encode descriptors: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"container.B","interfaces":["container.BInterface"],"lifetime":"Singleton"`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
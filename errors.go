@@ -1,6 +1,10 @@
 package container
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Package-level errors used throughout the dependency injection container.
 // These errors provide detailed information about various failure modes
@@ -24,6 +28,13 @@ var (
 	// using Container.CreateScope().
 	ErrScopedDependencyInGlobalScope = errors.New("called scoped dependency in global scope")
 
+	// ErrScopeClosed is returned when resolving a scoped service from a scope that was closed.
+	ErrScopeClosed = errors.New("scope is closed")
+
+	// ErrScopeWithoutTenant is returned when resolving a TenantSingleton service from a scope
+	// that was not created with Container.CreateTenantScope().
+	ErrScopeWithoutTenant = errors.New("scope has no tenant")
+
 	// ErrDependencyNotFound is returned when attempting to resolve a service that has not been
 	// registered with the container. This typically occurs when there's a mismatch between
 	// registered services and their dependencies, or when requesting an unregistered service.
@@ -62,6 +73,108 @@ var (
 	// that has already been built. Once a container is built, no new services can be registered.
 	ErrContainerAlreadyBuilt = errors.New("container already built")
 
+	// ErrHostedServiceTimeout is returned when Start or Stop of a hosted service does not
+	// complete within the timeout configured with WithStartTimeout or WithStopTimeout.
+	ErrHostedServiceTimeout = errors.New("hosted service timed out")
+
+	// ErrInvalidConfiguration is returned by Configure when a configuration struct cannot be
+	// bound from its sources or fails validation.
+	ErrInvalidConfiguration = errors.New("invalid configuration")
+
+	// ErrNotGenericType is returned when an open generic registration is not an
+	// instantiation of a generic struct type.
+	ErrNotGenericType = errors.New("not a generic type")
+
+	// ErrUnusedRegistration is returned during container building in strict usage mode
+	// when registrations are not reachable from any hosted service or entry point.
+	ErrUnusedRegistration = errors.New("unused registration")
+
 	// ErrCaptiveDependency occurs when a longer-lived service (e.g., singleton) depends on a shorter-lived service (e.g., scoped or transient).
 	ErrCaptiveDependency = errors.New("singleton calls scoped or transient")
 )
+
+// ResolutionStep is one call site on the path of a failed resolution.
+type ResolutionStep struct {
+	Name     string
+	Lifetime lifetime
+}
+
+// ResolutionError is returned when a service cannot be resolved because its construction
+// or the construction of one of its dependencies failed.
+//
+// Path lists the call sites from the requested service to the one that failed, and
+// Cause is the underlying error, so errors.Is(err, ErrFailedToBuildDependency) and
+// errors.Is(err, context.DeadlineExceeded) keep working through a ResolutionError.
+//
+// Example:
+//
+//	var resolutionErr *ResolutionError
+//	if errors.As(err, &resolutionErr) {
+//		failed := resolutionErr.Path[len(resolutionErr.Path)-1]
+//		log.Printf("%s (%s) failed: %v", failed.Name, failed.Lifetime, resolutionErr.Cause)
+//	}
+type ResolutionError struct {
+	Requested string
+	Path      []ResolutionStep
+	Cause     error
+}
+
+// Error renders the cause on the first line followed by the resolution path,
+// one call site per line.
+func (e *ResolutionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to resolve %s: %v", e.Requested, e.Cause)
+	for i, step := range e.Path {
+		fmt.Fprintf(&b, "\n%s-> %s (%s)", strings.Repeat("  ", i+1), step.Name, step.Lifetime)
+	}
+	return b.String()
+}
+
+func (e *ResolutionError) Unwrap() error { return e.Cause }
+
+// withResolutionStep returns a copy of err with step prepended to its path.
+// Errors that are not a *ResolutionError become the cause of a new one.
+// The original error is never modified because singletons cache their errors.
+func withResolutionStep(err error, step ResolutionStep) *ResolutionError {
+	var resolutionErr *ResolutionError
+	if !errors.As(err, &resolutionErr) {
+		return &ResolutionError{Requested: step.Name, Path: []ResolutionStep{step}, Cause: err}
+	}
+	return &ResolutionError{
+		Requested: step.Name,
+		Path:      append([]ResolutionStep{step}, resolutionErr.Path...),
+		Cause:     resolutionErr.Cause,
+	}
+}
+
+// withRequested returns a copy of err naming the requested type when err is a *ResolutionError.
+func withRequested(err error, requested string) error {
+	var resolutionErr *ResolutionError
+	if !errors.As(err, &resolutionErr) {
+		return err
+	}
+	copied := *resolutionErr
+	copied.Requested = requested
+	return &copied
+}
+
+// CaptiveDependencyError is returned during container building when a service depends,
+// directly or through transient intermediates, on a service with a lifetime forbidden
+// by the LifetimeRules of the container.
+//
+// Path lists the call sites from the capturing service to the captured one.
+type CaptiveDependencyError struct {
+	Path []ResolutionStep
+}
+
+func (e *CaptiveDependencyError) Error() string {
+	steps := make([]string, len(e.Path))
+	for i, step := range e.Path {
+		steps[i] = fmt.Sprintf("%s (%s)", step.Name, step.Lifetime)
+	}
+	first, last := e.Path[0], e.Path[len(e.Path)-1]
+	return fmt.Sprintf("%v: %s %s captures %s %s: %s",
+		ErrCaptiveDependency, first.Lifetime, first.Name, last.Lifetime, last.Name, strings.Join(steps, " -> "))
+}
+
+func (e *CaptiveDependencyError) Unwrap() error { return ErrCaptiveDependency }
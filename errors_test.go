@@ -0,0 +1,80 @@
+package container
+
+import (
+	"errors"
+	"testing"
+)
+
+type ResolutionRoot struct{}
+
+func (r *ResolutionRoot) Init(middle *ResolutionMiddle) error { return nil }
+
+type ResolutionMiddle struct{}
+
+func (r *ResolutionMiddle) Init(failing *ServiceWithFailingInit) error { return nil }
+
+type ResolutionRootInterface interface{ Root() }
+
+func (r *ResolutionRoot) Root() {}
+
+func TestResolutionError(t *testing.T) {
+	c := &Container{}
+	AddTransient[ResolutionRootInterface, ResolutionRoot](c)
+	AddScopedWithoutInterface[ResolutionMiddle](c)
+	AddSingletonWithoutInterface[ServiceWithFailingInit](c)
+	c.Build()
+
+	_, err := RequireServiceForScope[ResolutionRootInterface](c.CreateScope())
+	var resolutionErr *ResolutionError
+	if !errors.As(err, &resolutionErr) {
+		t.Fatalf("Expected *ResolutionError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, ErrFailedToBuildDependency) {
+		t.Errorf("Expected ErrFailedToBuildDependency in the chain, got %v", err)
+	}
+	if resolutionErr.Requested != "container.ResolutionRootInterface" {
+		t.Errorf("Unexpected requested type %s", resolutionErr.Requested)
+	}
+
+	expected := []ResolutionStep{
+		{Name: "container.ResolutionRoot", Lifetime: Transient},
+		{Name: "container.ResolutionMiddle", Lifetime: Scoped},
+		{Name: "container.ServiceWithFailingInit", Lifetime: Singleton},
+	}
+	if len(resolutionErr.Path) != len(expected) {
+		t.Fatalf("Expected path %v, got %v", expected, resolutionErr.Path)
+	}
+	for i, step := range expected {
+		if resolutionErr.Path[i] != step {
+			t.Errorf("Path mismatch at %d: expected %v, got %v", i, step, resolutionErr.Path[i])
+		}
+	}
+
+	rendered := "failed to resolve container.ResolutionRootInterface: failed to build dependency: for container.ServiceWithFailingInit: init failed\n" +
+		"  -> container.ResolutionRoot (Transient)\n" +
+		"    -> container.ResolutionMiddle (Scoped)\n" +
+		"      -> container.ServiceWithFailingInit (Singleton)"
+	if err.Error() != rendered {
+		t.Errorf("Unexpected rendering:\n%s", err.Error())
+	}
+}
+
+func TestResolutionErrorCachedSingletonNotModified(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[ServiceWithFailingInit](c)
+	AddTransientWithoutInterface[ResolutionMiddle](c)
+	c.Build()
+
+	for range 2 {
+		_, err := RequireServicePtr[ResolutionMiddle](c)
+		var resolutionErr *ResolutionError
+		if !errors.As(err, &resolutionErr) || len(resolutionErr.Path) != 2 {
+			t.Errorf("Expected path of 2 steps, got %v", err)
+		}
+	}
+	_, err := RequireServicePtr[ServiceWithFailingInit](c)
+	var resolutionErr *ResolutionError
+	if !errors.As(err, &resolutionErr) || len(resolutionErr.Path) != 1 {
+		t.Errorf("Expected path of 1 step, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type graphEdge struct {
+	to        int
+	parameter string // name of the Init parameter type
+}
+
+// dependencyGraph is the graph of the call sites of a container, with one node per
+// call site and one edge per Init parameter resolved to a registered call site.
+//
+// It is built once by Build with Tarjan's strongly connected components algorithm,
+// which yields both the dependency cycles and a construction order in linear time.
+type dependencyGraph struct {
+	sites []callSiteInterface // ordered by name, followed by sites of ancestors shadowed by a child
+	index map[callSiteInterface]int
+	edges [][]graphEdge
+	// order lists every node after the nodes it depends on, except inside cycles.
+	order []int
+	// cycles are the strongly connected components containing at least one cycle.
+	cycles [][]int
+}
+
+func (c *Container) newDependencyGraph() *dependencyGraph {
+	sites := c.uniqueSites()
+	g := &dependencyGraph{
+		sites: sites,
+		index: make(map[callSiteInterface]int, len(sites)),
+		edges: make([][]graphEdge, len(sites)),
+		order: make([]int, 0, len(sites)),
+	}
+	for i, site := range sites {
+		g.index[site] = i
+	}
+	for i := 0; i < len(g.sites); i++ {
+		site := g.sites[i]
+		for _, depName := range site.Deps() {
+			if dep, ok := c.lookupFor(site, depName); ok {
+				g.edges[i] = append(g.edges[i], graphEdge{to: g.node(dep), parameter: depName})
+			}
+		}
+	}
+	g.tarjan()
+	return g
+}
+
+// node returns the node of site, adding it when site is not registered under its name.
+func (g *dependencyGraph) node(site callSiteInterface) int {
+	if i, ok := g.index[site]; ok {
+		return i
+	}
+	g.index[site] = len(g.sites)
+	g.sites = append(g.sites, site)
+	g.edges = append(g.edges, nil)
+	return len(g.sites) - 1
+}
+
+func (g *dependencyGraph) tarjan() {
+	var (
+		counter int
+		indexes = make([]int, len(g.sites)) // zero for unvisited nodes
+		lowLink = make([]int, len(g.sites))
+		onStack = make([]bool, len(g.sites))
+		stack   []int
+	)
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		counter++
+		indexes[v], lowLink[v] = counter, counter
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, edge := range g.edges[v] {
+			if indexes[edge.to] == 0 {
+				strongConnect(edge.to)
+				lowLink[v] = min(lowLink[v], lowLink[edge.to])
+			} else if onStack[edge.to] {
+				lowLink[v] = min(lowLink[v], indexes[edge.to])
+			}
+		}
+		if lowLink[v] != indexes[v] {
+			return
+		}
+		// v is the root of a component. Components are completed after every component
+		// they depend on, which makes the sequence of components a construction order.
+		var component []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		g.order = append(g.order, component...)
+		if len(component) > 1 || g.hasEdge(v, v) {
+			slices.Sort(component)
+			g.cycles = append(g.cycles, component)
+		}
+	}
+	for v := range g.sites {
+		if indexes[v] == 0 {
+			strongConnect(v)
+		}
+	}
+	slices.SortFunc(g.cycles, func(a, b []int) int { return a[0] - b[0] })
+}
+
+func (g *dependencyGraph) hasEdge(from, to int) bool {
+	return slices.ContainsFunc(g.edges[from], func(edge graphEdge) bool { return edge.to == to })
+}
+
+// maxCyclesPerComponent bounds the number of cycles reported for one strongly connected
+// component, whose number of elementary cycles can grow exponentially with its size.
+const maxCyclesPerComponent = 100
+
+// elementaryCycles returns the nodes of the elementary cycles of the component, at most
+// limit of them, shortest first. Each cycle starts at its smallest node and is found once
+// with Johnson's algorithm.
+func (g *dependencyGraph) elementaryCycles(component []int, limit int) [][]int {
+	var (
+		cycles    [][]int
+		blocked   = make(map[int]bool)
+		blockedBy = make(map[int]map[int]bool)
+		path      []int
+		start     int
+	)
+	var unblock func(v int)
+	unblock = func(v int) {
+		blocked[v] = false
+		for w := range blockedBy[v] {
+			delete(blockedBy[v], w)
+			if blocked[w] {
+				unblock(w)
+			}
+		}
+	}
+	var circuit func(v int) bool
+	circuit = func(v int) bool {
+		found := false
+		path = append(path, v)
+		blocked[v] = true
+		for _, w := range g.successors(v, component, start) {
+			if len(cycles) == limit {
+				break
+			}
+			if w == start {
+				cycles = append(cycles, slices.Clone(path))
+				found = true
+			} else if !blocked[w] && circuit(w) {
+				found = true
+			}
+		}
+		if found {
+			unblock(v)
+		} else {
+			for _, w := range g.successors(v, component, start) {
+				if blockedBy[w] == nil {
+					blockedBy[w] = make(map[int]bool)
+				}
+				blockedBy[w][v] = true
+			}
+		}
+		path = path[:len(path)-1]
+		return found
+	}
+	// Cycles through start use only nodes of the component from start on
+	for _, start = range component {
+		if len(cycles) == limit {
+			break
+		}
+		clear(blocked)
+		clear(blockedBy)
+		circuit(start)
+	}
+	slices.SortStableFunc(cycles, func(a, b []int) int { return len(a) - len(b) })
+	return cycles
+}
+
+// successors returns the distinct nodes of the component, not smaller than start,
+// that v depends on.
+func (g *dependencyGraph) successors(v int, component []int, start int) []int {
+	var successors []int
+	for _, edge := range g.edges[v] {
+		if _, ok := slices.BinarySearch(component, edge.to); ok && edge.to >= start && !slices.Contains(successors, edge.to) {
+			successors = append(successors, edge.to)
+		}
+	}
+	return successors
+}
+
+// cycleEdges returns the edges of the cycle going through the given nodes.
+func (g *dependencyGraph) cycleEdges(nodes []int) []DependencyEdge {
+	edges := make([]DependencyEdge, len(nodes))
+	for i, from := range nodes {
+		to := nodes[(i+1)%len(nodes)]
+		j := slices.IndexFunc(g.edges[from], func(edge graphEdge) bool { return edge.to == to })
+		edges[i] = g.dependencyEdge(from, g.edges[from][j])
+	}
+	return edges
+}
+
+func (g *dependencyGraph) dependencyEdge(from int, edge graphEdge) DependencyEdge {
+	return DependencyEdge{From: g.sites[from].Name(), To: g.sites[edge.to].Name(), Parameter: edge.parameter}
+}
+
+// cycleError returns a *CycleError for every elementary cycle, or nil.
+func (g *dependencyGraph) cycleError() error {
+	if len(g.cycles) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, component := range g.cycles {
+		cycles := g.elementaryCycles(component, maxCyclesPerComponent+1)
+		for i, cycle := range cycles {
+			if i == maxCyclesPerComponent {
+				names := make([]string, len(component))
+				for j, v := range component {
+					names[j] = g.sites[v].Name()
+				}
+				errs = append(errs, fmt.Errorf("%w: more than %d cycles among %s",
+					ErrCircleDependency, maxCyclesPerComponent, strings.Join(names, ", ")))
+				break
+			}
+			errs = append(errs, &CycleError{Cycle: g.cycleEdges(cycle)})
+		}
+	}
+	return fmt.Errorf("dependency cycles found: %w", errors.Join(errs...))
+}
+
+// waves groups the given sites into waves so that every site depends, directly or
+// through sites that are not given, only on sites of earlier waves.
+// Waves hold indexes into sites in increasing order.
+func (g *dependencyGraph) waves(sites []callSiteInterface) [][]int {
+	members := make(map[int]bool, len(sites))
+	for _, site := range sites {
+		members[g.index[site]] = true
+	}
+	// height is the largest number of given sites on a dependency chain starting at a node
+	height := make([]int, len(g.sites))
+	for _, v := range g.order {
+		for _, edge := range g.edges[v] {
+			height[v] = max(height[v], height[edge.to])
+		}
+		if members[v] {
+			height[v]++
+		}
+	}
+
+	var waves [][]int
+	for i, site := range sites {
+		level := height[g.index[site]]
+		for len(waves) < level {
+			waves = append(waves, nil)
+		}
+		waves[level-1] = append(waves[level-1], i)
+	}
+	return waves
+}
+
+// DependencyEdge is an Init parameter of a service resolved to another service.
+type DependencyEdge struct {
+	From string
+	To   string
+	// Parameter is the type of the Init parameter of From.
+	Parameter string
+}
+
+func (e DependencyEdge) String() string { return e.From + " -> " + e.To }
+
+// CycleError is returned during container building for every elementary cycle of the
+// dependency graph: every way services depend on themselves is reported once.
+type CycleError struct {
+	// Cycle lists the edges of the cycle in dependency order.
+	Cycle []DependencyEdge
+}
+
+func (e *CycleError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s", ErrCircleDependency, e.Cycle[0].From)
+	for _, edge := range e.Cycle {
+		fmt.Fprintf(&b, " -> %s", edge.To)
+	}
+	return b.String()
+}
+
+func (e *CycleError) Unwrap() error { return ErrCircleDependency }
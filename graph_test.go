@@ -0,0 +1,148 @@
+package container
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type SelfLoop struct{}
+
+func (*SelfLoop) Init(s *SelfLoop) error { return nil }
+
+// CycleA -> CycleB -> CycleC -> CycleA and CycleB -> CycleA form one component
+type CycleA struct{}
+type CycleB struct{}
+type CycleC struct{}
+
+func (*CycleA) Init(b *CycleB) error            { return nil }
+func (*CycleB) Init(c *CycleC, a *CycleA) error { return nil }
+func (*CycleC) Init(a *CycleA) error            { return nil }
+
+func cycleErrors(t *testing.T, c *Container) []*CycleError {
+	t.Helper()
+	err := buildPanic(c)
+	if !errors.Is(errors.Unwrap(err), ErrCircleDependency) {
+		t.Fatalf("Expected ErrCircleDependency, got %v", err)
+	}
+	var cycles []*CycleError
+	for _, err := range errors.Unwrap(err).(interface{ Unwrap() []error }).Unwrap() {
+		var cycleErr *CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("Expected CycleError, got %v", err)
+		}
+		cycles = append(cycles, cycleErr)
+	}
+	return cycles
+}
+
+func TestCyclesAreReportedOnce(t *testing.T) {
+	c := GetContainer()
+	AddTransientWithoutInterface[CircleOne](c)
+	AddTransientWithoutInterface[CircleTwo](c)
+	AddSingletonWithoutInterface[SelfLoop](c)
+
+	cycles := cycleErrors(t, c)
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 cycles, got %v", cycles)
+	}
+	expected := []DependencyEdge{
+		{From: "container.CircleOne", To: "container.CircleTwo", Parameter: "*container.CircleTwo"},
+		{From: "container.CircleTwo", To: "container.CircleOne", Parameter: "*container.CircleOne"},
+	}
+	if !slices.Equal(cycles[0].Cycle, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycles[0].Cycle)
+	}
+	if cycles[1].Error() != "circle dependency: container.SelfLoop -> container.SelfLoop" {
+		t.Errorf("Unexpected self loop error %q", cycles[1].Error())
+	}
+}
+
+// CycleHub -> CycleSpokeOne -> CycleHub and CycleHub -> CycleSpokeTwo -> CycleHub
+type CycleHub struct{}
+type CycleSpokeOne struct{}
+type CycleSpokeTwo struct{}
+
+func (*CycleHub) Init(one *CycleSpokeOne, two *CycleSpokeTwo) error { return nil }
+func (*CycleSpokeOne) Init(h *CycleHub) error                       { return nil }
+func (*CycleSpokeTwo) Init(h *CycleHub) error                       { return nil }
+
+func cycleMessages(cycles []*CycleError) []string {
+	messages := make([]string, len(cycles))
+	for i, cycle := range cycles {
+		messages[i] = cycle.Error()
+	}
+	return messages
+}
+
+func TestEveryCycleInComponentIsReported(t *testing.T) {
+	c := &Container{}
+	AddTransientWithoutInterface[CycleA](c)
+	AddTransientWithoutInterface[CycleB](c)
+	AddTransientWithoutInterface[CycleC](c)
+	AddTransientWithoutInterface[CycleHub](c)
+	AddTransientWithoutInterface[CycleSpokeOne](c)
+	AddTransientWithoutInterface[CycleSpokeTwo](c)
+
+	cycles := cycleErrors(t, c)
+	expected := []string{
+		"circle dependency: container.CycleA -> container.CycleB -> container.CycleA",
+		"circle dependency: container.CycleA -> container.CycleB -> container.CycleC -> container.CycleA",
+		"circle dependency: container.CycleHub -> container.CycleSpokeOne -> container.CycleHub",
+		"circle dependency: container.CycleHub -> container.CycleSpokeTwo -> container.CycleHub",
+	}
+	if messages := cycleMessages(cycles); !slices.Equal(messages, expected) {
+		t.Errorf("Expected cycles %q, got %q", expected, messages)
+	}
+	edge := DependencyEdge{From: "container.CycleHub", To: "container.CycleSpokeTwo", Parameter: "*container.CycleSpokeTwo"}
+	if cycles[3].Cycle[0] != edge {
+		t.Errorf("Expected edge %v, got %v", edge, cycles[3].Cycle[0])
+	}
+}
+
+func TestElementaryCyclesLimit(t *testing.T) {
+	// Complete graph of 4 nodes: 6 cycles of 2 nodes, 8 of 3 nodes and 6 of 4 nodes
+	g := &dependencyGraph{edges: make([][]graphEdge, 4)}
+	for from := range 4 {
+		for to := range 4 {
+			if from != to {
+				g.edges[from] = append(g.edges[from], graphEdge{to: to})
+			}
+		}
+	}
+	component := []int{0, 1, 2, 3}
+	if cycles := g.elementaryCycles(component, 100); len(cycles) != 20 {
+		t.Errorf("Expected 20 cycles, got %d", len(cycles))
+	}
+	if cycles := g.elementaryCycles(component, 5); len(cycles) != 5 {
+		t.Errorf("Expected 5 cycles, got %d", len(cycles))
+	}
+}
+
+func TestDependencyGraphOrder(t *testing.T) {
+	c := BuildContainer()
+
+	position := make(map[string]int)
+	for i, v := range c.graph.order {
+		position[c.graph.sites[v].Name()] = i
+	}
+	for _, edge := range [][2]string{
+		{"container.A", "container.Counter"},
+		{"container.B", "container.A"},
+		{"container.C", "container.A"},
+	} {
+		if position[edge[0]] < position[edge[1]] {
+			t.Errorf("Expected %s to be ordered after %s", edge[0], edge[1])
+		}
+	}
+
+	sites := []callSiteInterface{
+		c.callSitesRegistry["container.B"],
+		c.callSitesRegistry["container.Counter"],
+		c.callSitesRegistry["container.C"],
+	}
+	waves := c.graph.waves(sites)
+	if len(waves) != 2 || !slices.Equal(waves[0], []int{1}) || !slices.Equal(waves[1], []int{0, 2}) {
+		t.Errorf("Unexpected waves %v", waves)
+	}
+}
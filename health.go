@@ -0,0 +1,280 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// HealthStatus is the status reported by a health check.
+//
+// Statuses are ordered from worst to best, so the aggregated status of a report is
+// the lowest status of its entries.
+type HealthStatus int
+
+const (
+	Unhealthy HealthStatus = iota
+	Degraded
+	Healthy
+)
+
+func (s HealthStatus) String() string {
+	switch s {
+	case Unhealthy:
+		return "Unhealthy"
+	case Degraded:
+		return "Degraded"
+	case Healthy:
+		return "Healthy"
+	default:
+		return fmt.Sprintf("HealthStatus(%d)", int(s))
+	}
+}
+
+// MarshalJSON encodes the status as its name.
+func (s HealthStatus) MarshalJSON() ([]byte, error) { return json.Marshal(s.String()) }
+
+// Well-known health check tags.
+const (
+	// TagLive marks checks telling whether the process is alive.
+	TagLive = "live"
+	// TagReady marks checks telling whether the application is ready to serve requests.
+	TagReady = "ready"
+)
+
+// DefaultHealthCheckTimeout is used for health checks registered without WithHealthCheckTimeout.
+const DefaultHealthCheckTimeout = 30 * time.Second
+
+// HostedServicesHealthCheckName is the name of the built-in check that reports
+// the application as not ready until StartAsync has completed.
+const HostedServicesHealthCheckName = "hosted-services"
+
+// HealthCheckResult is the result of a single health check.
+type HealthCheckResult struct {
+	Status      HealthStatus
+	Description string
+	Err         error
+}
+
+// IHealthCheck is implemented by services that check the health of a component.
+//
+// Health checks are registered with AddHealthCheck and run by HealthService.
+// The provided context is cancelled when the check times out.
+type IHealthCheck interface {
+	CheckHealth(ctx context.Context) HealthCheckResult
+}
+
+// HealthEntry is the result of one health check in a HealthReport.
+type HealthEntry struct {
+	Status      HealthStatus  `json:"status"`
+	Description string        `json:"description,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+// HealthReport is the aggregated result of running health checks.
+type HealthReport struct {
+	Status        HealthStatus           `json:"status"`
+	TotalDuration time.Duration          `json:"totalDuration"`
+	Entries       map[string]HealthEntry `json:"entries"`
+}
+
+type healthCheckRegistration struct {
+	name    string
+	site    callSiteInterface // nil for the built-in hosted services check
+	tags    []string
+	timeout time.Duration
+	check   func(ctx context.Context, s *Scope) (IHealthCheck, error) // used when site is nil
+}
+
+// resolve returns the health check of the registration.
+func (r healthCheckRegistration) resolve(ctx context.Context, s *Scope) (IHealthCheck, error) {
+	if r.site == nil {
+		return r.check(ctx, s)
+	}
+	instance, err := r.site.Build(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return instance.(IHealthCheck), nil
+}
+
+// WithTags sets the tags of a health check, e.g. TagLive or TagReady.
+func WithTags(tags ...string) Option {
+	return func(o *serviceOptions) { o.tags = append(o.tags, tags...) }
+}
+
+// WithHealthCheckTimeout sets how long a health check may run before it is reported as Unhealthy.
+func WithHealthCheckTimeout(timeout time.Duration) Option {
+	return func(o *serviceOptions) { o.healthCheckTimeout = timeout }
+}
+
+// AddHealthCheck registers a health check implemented by T under the given name.
+//
+// T is registered as a singleton, so it can receive dependencies through its Init method
+// like any other service. Use WithTags and WithHealthCheckTimeout to configure the check.
+//
+// Example:
+//
+//	AddHealthCheck[DatabaseHealthCheck](c, "database", WithTags(TagReady))
+func AddHealthCheck[T any](c *Container, name string, opts ...Option) {
+	if _, ok := any(new(T)).(IHealthCheck); !ok {
+		panic(fmt.Errorf("%w: %s should implement IHealthCheck", ErrShouldImplementInterface, nameForT[T]()))
+	}
+	for _, registration := range c.healthChecks {
+		if registration.name == name {
+			panic(fmt.Errorf("%w: Health check %s already exists in container", ErrTypeAlreadyRegistered, name))
+		}
+	}
+	site := add[T](c, Singleton, opts...)
+	c.healthChecks = append(c.healthChecks, healthCheckRegistration{
+		name:    name,
+		site:    site,
+		tags:    site.options.tags,
+		timeout: site.options.healthCheckTimeout,
+	})
+}
+
+// HealthService runs the health checks registered in the container.
+//
+// It is registered in every container during Build and can be injected as *HealthService.
+type HealthService struct {
+	container *Container
+}
+
+// Health returns the health service of the container.
+func (c *Container) Health() *HealthService {
+	if c.health == nil {
+		c.health = &HealthService{container: c}
+	}
+	return c.health
+}
+
+func (h *HealthService) registrations() []healthCheckRegistration {
+	lifetime := h.container.appLifetime()
+	hosted := healthCheckRegistration{
+		name:    HostedServicesHealthCheckName,
+		tags:    []string{TagReady},
+		timeout: DefaultHealthCheckTimeout,
+		check: func(context.Context, *Scope) (IHealthCheck, error) {
+			return hostedServicesHealthCheck{lifetime: lifetime}, nil
+		},
+	}
+	return append([]healthCheckRegistration{hosted}, h.container.healthChecks...)
+}
+
+// Check runs all health checks that have at least one of the given tags, or all checks
+// when no tags are given. Checks run concurrently, each bounded by its timeout.
+func (h *HealthService) Check(ctx context.Context, tags ...string) HealthReport {
+	var selected []healthCheckRegistration
+	for _, registration := range h.registrations() {
+		if len(tags) == 0 || slices.ContainsFunc(registration.tags, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		}) {
+			selected = append(selected, registration)
+		}
+	}
+
+	begin := time.Now()
+	entries := make([]HealthEntry, len(selected))
+	indexes := make([]int, len(selected))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	ran := make([]bool, len(selected))
+	_ = runParallel(ctx, indexes, 0, func(ctx context.Context, i int) error {
+		entries[i], ran[i] = h.run(ctx, selected[i]), true
+		return nil
+	})
+	for i, registration := range selected {
+		if !ran[i] {
+			entries[i] = HealthEntry{Status: Unhealthy, Description: "health check not run", Error: ctx.Err().Error(), Tags: registration.tags}
+		}
+	}
+
+	report := HealthReport{
+		Status:        Healthy,
+		TotalDuration: time.Since(begin),
+		Entries:       make(map[string]HealthEntry, len(selected)),
+	}
+	for i, registration := range selected {
+		report.Entries[registration.name] = entries[i]
+		report.Status = min(report.Status, entries[i].Status)
+	}
+	return report
+}
+
+func (h *HealthService) run(ctx context.Context, registration healthCheckRegistration) HealthEntry {
+	timeout := registration.timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	begin := time.Now()
+	results := make(chan HealthCheckResult, 1)
+	go func() {
+		check, err := registration.resolve(ctx, h.container.global)
+		if err != nil {
+			results <- HealthCheckResult{Status: Unhealthy, Description: "failed to resolve health check", Err: err}
+			return
+		}
+		results <- check.CheckHealth(ctx)
+	}()
+
+	var result HealthCheckResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		result = HealthCheckResult{
+			Status:      Unhealthy,
+			Description: fmt.Sprintf("health check timed out after %s", timeout),
+			Err:         ctx.Err(),
+		}
+	}
+
+	entry := HealthEntry{
+		Status:      result.Status,
+		Description: result.Description,
+		Duration:    time.Since(begin),
+		Tags:        registration.tags,
+	}
+	if result.Err != nil {
+		entry.Error = result.Err.Error()
+	}
+	return entry
+}
+
+// ServeHTTP runs the health checks and writes the report as JSON.
+//
+// The "tag" query parameter, which may be repeated, selects checks by tag.
+// The response status is 200 for Healthy and Degraded reports and 503 for Unhealthy ones.
+func (h *HealthService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context(), r.URL.Query()["tag"]...)
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == Unhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+type hostedServicesHealthCheck struct {
+	lifetime *applicationLifetime
+}
+
+func (h hostedServicesHealthCheck) CheckHealth(ctx context.Context) HealthCheckResult {
+	if closed(h.lifetime.ApplicationStopping()) {
+		return HealthCheckResult{Status: Unhealthy, Description: "hosted services are stopping"}
+	}
+	if !closed(h.lifetime.ApplicationStarted()) {
+		return HealthCheckResult{Status: Unhealthy, Description: "hosted services are not started"}
+	}
+	return HealthCheckResult{Status: Healthy, Description: "hosted services are started"}
+}
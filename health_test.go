@@ -0,0 +1,116 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type DatabaseHealthCheck struct{}
+
+func (d *DatabaseHealthCheck) Init() error { return nil }
+func (d *DatabaseHealthCheck) CheckHealth(ctx context.Context) HealthCheckResult {
+	return HealthCheckResult{Status: Degraded, Description: "replica lag"}
+}
+
+type SlowHealthCheck struct{}
+
+func (s *SlowHealthCheck) Init() error { return nil }
+func (s *SlowHealthCheck) CheckHealth(ctx context.Context) HealthCheckResult {
+	<-ctx.Done()
+	return HealthCheckResult{Status: Healthy}
+}
+
+type BrokenHealthCheck struct{}
+
+func (b *BrokenHealthCheck) Init() error { return nil }
+func (b *BrokenHealthCheck) CheckHealth(ctx context.Context) HealthCheckResult {
+	return HealthCheckResult{Status: Unhealthy, Err: errors.New("disk full")}
+}
+
+func TestHealthServiceAggregation(t *testing.T) {
+	c := &Container{}
+	AddHealthCheck[DatabaseHealthCheck](c, "database", WithTags(TagReady))
+	AddHealthCheck[BrokenHealthCheck](c, "disk", WithTags(TagLive))
+	c.Build()
+
+	health, err := RequireService[*HealthService](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve HealthService: %v", err)
+	}
+
+	live := health.Check(context.Background(), TagLive)
+	if live.Status != Unhealthy || len(live.Entries) != 1 || live.Entries["disk"].Error != "disk full" {
+		t.Errorf("Unexpected live report: %+v", live)
+	}
+
+	if err := c.StartAsync(context.Background()); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	ready := health.Check(context.Background(), TagReady)
+	if ready.Status != Degraded || len(ready.Entries) != 2 {
+		t.Errorf("Unexpected ready report: %+v", ready)
+	}
+	if ready.Entries[HostedServicesHealthCheckName].Status != Healthy {
+		t.Errorf("Hosted services should be ready after StartAsync, got %+v", ready.Entries)
+	}
+}
+
+func TestHealthServiceNotReadyBeforeStart(t *testing.T) {
+	c := &Container{}
+	AddHostedService[MyHostedService](c)
+	c.Build()
+
+	report := c.Health().Check(context.Background(), TagReady)
+	if report.Status != Unhealthy {
+		t.Errorf("Expected Unhealthy before StartAsync, got %s", report.Status)
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	c := &Container{}
+	AddHealthCheck[SlowHealthCheck](c, "slow", WithHealthCheckTimeout(10*time.Millisecond))
+	c.Build()
+
+	report := c.Health().Check(context.Background())
+	entry := report.Entries["slow"]
+	if entry.Status != Unhealthy || entry.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected timed out check to be Unhealthy, got %+v", entry)
+	}
+}
+
+func TestHealthServiceHTTPHandler(t *testing.T) {
+	c := &Container{}
+	AddHealthCheck[DatabaseHealthCheck](c, "database", WithTags(TagLive))
+	c.Build()
+
+	recorder := httptest.NewRecorder()
+	c.Health().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health?tag=live", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var body struct {
+		Status  string `json:"status"`
+		Entries map[string]struct {
+			Status      string `json:"status"`
+			Description string `json:"description"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	if body.Status != "Degraded" || body.Entries["database"].Description != "replica lag" {
+		t.Errorf("Unexpected body: %s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	c.Health().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health?tag=ready", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 before StartAsync, got %d", recorder.Code)
+	}
+}
@@ -1,6 +1,11 @@
 package container
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 // IHostedService defines the interface for long-running background services.
 type IHostedService interface {
@@ -13,3 +18,69 @@ type IHostedService interface {
 	// The provided context typically includes a timeout for the shutdown process.
 	Stop(context.Context) error
 }
+
+// hostedServiceEntry is a built hosted service.
+type hostedServiceEntry struct {
+	site    callSiteInterface
+	service IHostedService
+}
+
+// hostedServiceWaves groups hosted services into waves. Every service of a wave depends
+// only on services of earlier waves. Indexes inside a wave keep registration order.
+func (c *Container) hostedServiceWaves() [][]int {
+	sites := make([]callSiteInterface, len(c.hostedServices))
+	for i, entry := range c.hostedServices {
+		sites[i] = entry.site
+	}
+	return c.graph.waves(sites)
+}
+
+// WithStartTimeout bounds how long Start of a hosted service may run.
+//
+// The service receives a context derived from the StartAsync context that expires after
+// the timeout. If Start does not return in time, starting fails with ErrHostedServiceTimeout.
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(o *serviceOptions) { o.startTimeout = timeout }
+}
+
+// WithStopTimeout bounds how long Stop of a hosted service may run.
+//
+// The service receives a context derived from the StopAsync context that expires after
+// the timeout, so the time spent stopping other services does not consume its budget.
+// The deadline of the StopAsync context still applies when it is earlier. If Stop does
+// not return in time, stopping fails with ErrHostedServiceTimeout and the next service
+// is stopped.
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(o *serviceOptions) { o.stopTimeout = timeout }
+}
+
+// errServiceTimeout is the cause of the context of a hosted service call whose own
+// timeout expired, as opposed to the deadline of the caller.
+var errServiceTimeout = errors.New("hosted service timeout")
+
+// callWithTimeout calls fn with a context bounded by timeout and returns
+// ErrHostedServiceTimeout if fn does not return before the timeout expires.
+// When ctx is done first, its error is returned. A timeout of zero or less
+// calls fn with ctx unchanged.
+func callWithTimeout(ctx context.Context, timeout time.Duration, action, name string, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errServiceTimeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() { result <- fn(ctx) }()
+	select {
+	case err := <-result:
+		if err != nil && context.Cause(ctx) == errServiceTimeout {
+			return fmt.Errorf("%w: %s %s after %s: %w", ErrHostedServiceTimeout, action, name, timeout, err)
+		}
+		return err
+	case <-ctx.Done():
+		if context.Cause(ctx) == errServiceTimeout {
+			return fmt.Errorf("%w: %s %s after %s", ErrHostedServiceTimeout, action, name, timeout)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,272 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ParallelTracker struct {
+	mu      sync.Mutex
+	running int32
+	peak    int32
+	started []string
+}
+
+func (p *ParallelTracker) Init() error { return nil }
+func (p *ParallelTracker) enter(name string) {
+	running := atomic.AddInt32(&p.running, 1)
+	p.mu.Lock()
+	p.peak = max(p.peak, running)
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	p.mu.Lock()
+	p.started = append(p.started, name)
+	p.mu.Unlock()
+	atomic.AddInt32(&p.running, -1)
+}
+
+type ParallelFirst struct{ tracker *ParallelTracker }
+
+func (p *ParallelFirst) Init(t *ParallelTracker) error {
+	p.tracker = t
+	return nil
+}
+func (p *ParallelFirst) Start(ctx context.Context) error { p.tracker.enter("first"); return nil }
+func (p *ParallelFirst) Stop(ctx context.Context) error  { return nil }
+
+type ParallelSecond struct{ tracker *ParallelTracker }
+
+func (p *ParallelSecond) Init(t *ParallelTracker) error {
+	p.tracker = t
+	return nil
+}
+func (p *ParallelSecond) Start(ctx context.Context) error { p.tracker.enter("second"); return nil }
+func (p *ParallelSecond) Stop(ctx context.Context) error  { return nil }
+
+type ParallelDependent struct{ tracker *ParallelTracker }
+
+func (p *ParallelDependent) Init(t *ParallelTracker, first *ParallelFirst) error {
+	p.tracker = t
+	return nil
+}
+func (p *ParallelDependent) Start(ctx context.Context) error {
+	p.tracker.enter("dependent")
+	return nil
+}
+func (p *ParallelDependent) Stop(ctx context.Context) error { return nil }
+
+func TestStartParallelAsyncWaves(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[ParallelTracker](c)
+	AddHostedService[ParallelDependent](c)
+	AddHostedService[ParallelFirst](c)
+	AddHostedService[ParallelSecond](c)
+	c.Build()
+
+	waves := c.hostedServiceWaves()
+	if len(waves) != 2 || len(waves[0]) != 2 || len(waves[1]) != 1 {
+		t.Fatalf("Expected waves [[first second] [dependent]], got %v", waves)
+	}
+
+	err := c.StartParallelAsync(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("StartParallelAsync failed: %v", err)
+	}
+
+	tracker, _ := RequireServicePtr[ParallelTracker](c)
+	if tracker.peak != 2 {
+		t.Errorf("Expected 2 services to start concurrently, got %d", tracker.peak)
+	}
+	if len(tracker.started) != 3 || tracker.started[2] != "dependent" {
+		t.Errorf("Expected dependent to start last, got %v", tracker.started)
+	}
+}
+
+func TestStartParallelAsyncConcurrencyLimit(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[ParallelTracker](c)
+	AddHostedService[ParallelFirst](c)
+	AddHostedService[ParallelSecond](c)
+	c.Build()
+
+	err := c.StartParallelAsync(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("StartParallelAsync failed: %v", err)
+	}
+
+	tracker, _ := RequireServicePtr[ParallelTracker](c)
+	if tracker.peak != 1 {
+		t.Errorf("Expected at most 1 concurrent start, got %d", tracker.peak)
+	}
+}
+
+func TestStartParallelAsyncCancelled(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[ParallelTracker](c)
+	AddHostedService[ParallelSecond](c)
+	AddHostedService[ParallelFirst](c)
+	AddHostedService[ParallelDependent](c)
+	c.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	err := c.StartParallelAsync(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	tracker, _ := RequireServicePtr[ParallelTracker](c)
+	if len(tracker.started) != 1 || tracker.started[0] != "second" {
+		t.Errorf("Expected only the service started before the deadline, got %v", tracker.started)
+	}
+}
+
+type SecondFailingStartService struct{}
+
+func (f *SecondFailingStartService) Init() error { return nil }
+func (f *SecondFailingStartService) Start(ctx context.Context) error {
+	return fmt.Errorf("second startup failed")
+}
+func (f *SecondFailingStartService) Stop(ctx context.Context) error { return nil }
+
+func TestStartParallelAsyncErrors(t *testing.T) {
+	c := &Container{}
+	AddHostedService[FailingStartService](c)
+	AddHostedService[MyHostedService](c)
+	AddHostedService[SecondFailingStartService](c)
+	c.Build()
+
+	err := c.StartParallelAsync(context.Background(), 0)
+	if err == nil {
+		t.Fatalf("Expected error when services fail to start")
+	}
+	expected := "failed to start hosted service #0 container.FailingStartService: startup failed\n" +
+		"failed to start hosted service #2 container.SecondFailingStartService: second startup failed"
+	if err.Error() != expected {
+		t.Errorf("Expected errors in registration order, got %q", err.Error())
+	}
+
+	svc, _ := RequireServicePtr[MyHostedService](c)
+	if !svc.started {
+		t.Errorf("Service in the same wave should have started")
+	}
+}
+
+func TestStartParallelAsyncBeforeBuild(t *testing.T) {
+	c := &Container{}
+	AddHostedService[MyHostedService](c)
+
+	err := c.StartParallelAsync(context.Background(), 0)
+	if !errors.Is(err, ErrContainerNotBuilt) {
+		t.Errorf("Expected ErrContainerNotBuilt, got %v", err)
+	}
+}
+
+type HangingStopService struct{}
+
+func (h *HangingStopService) Init() error                     { return nil }
+func (h *HangingStopService) Start(ctx context.Context) error { return nil }
+func (h *HangingStopService) Stop(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type ContextCheckingStopService struct {
+	stopErr error
+}
+
+func (s *ContextCheckingStopService) Init() error                     { return nil }
+func (s *ContextCheckingStopService) Start(ctx context.Context) error { return nil }
+func (s *ContextCheckingStopService) Stop(ctx context.Context) error {
+	s.stopErr = ctx.Err()
+	return nil
+}
+
+func TestStopTimeoutPerService(t *testing.T) {
+	c := &Container{}
+	AddHostedService[ContextCheckingStopService](c, WithStopTimeout(time.Second))
+	AddHostedService[HangingStopService](c, WithStopTimeout(20*time.Millisecond))
+	c.Build()
+
+	if err := c.StartAsync(context.Background()); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := c.StopAsync(ctx)
+	if !errors.Is(err, ErrHostedServiceTimeout) {
+		t.Fatalf("Expected ErrHostedServiceTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "container.HangingStopService") {
+		t.Errorf("Expected timed out service name in error, got %v", err)
+	}
+
+	svc, _ := RequireServicePtr[ContextCheckingStopService](c)
+	if svc.stopErr != nil {
+		t.Errorf("Service with its own stop timeout should get a live context, got %v", svc.stopErr)
+	}
+}
+
+func TestStopTimeoutBoundedByStopAsyncContext(t *testing.T) {
+	c := &Container{}
+	AddHostedService[HangingStopService](c, WithStopTimeout(time.Minute))
+	c.Build()
+
+	if err := c.StartAsync(context.Background()); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	err := c.StopAsync(ctx)
+	if errors.Is(err, ErrHostedServiceTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline of the StopAsync context, got %v", err)
+	}
+	if time.Since(begin) > 500*time.Millisecond {
+		t.Errorf("StopAsync should not outlive its context")
+	}
+}
+
+type HangingStartService struct{}
+
+func (h *HangingStartService) Init() error { return nil }
+func (h *HangingStartService) Start(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+func (h *HangingStartService) Stop(ctx context.Context) error { return nil }
+
+func TestStartTimeout(t *testing.T) {
+	c := &Container{}
+	AddHostedService[HangingStartService](c, WithStartTimeout(10*time.Millisecond))
+	c.Build()
+
+	begin := time.Now()
+	err := c.StartAsync(context.Background())
+	if !errors.Is(err, ErrHostedServiceTimeout) {
+		t.Fatalf("Expected ErrHostedServiceTimeout, got %v", err)
+	}
+	if time.Since(begin) > 500*time.Millisecond {
+		t.Errorf("StartAsync should not wait for the hanging Start")
+	}
+}
+
+func TestStartTimeoutOfCaller(t *testing.T) {
+	c := &Container{}
+	AddHostedService[HangingStartService](c, WithStartTimeout(time.Minute))
+	c.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.StartAsync(ctx)
+	if errors.Is(err, ErrHostedServiceTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline of the StartAsync context, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package container
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResolveInfo describes the call site being resolved.
+type ResolveInfo struct {
+	Name     string
+	Lifetime lifetime
+	Scope    *Scope
+}
+
+// ResolveInterceptor wraps every resolution of a call site, including the resolution
+// of dependencies and of values registered with AddValue.
+//
+// Intercept must call next to resolve the service, and may observe or replace the
+// returned instance or error. A replaced instance must be assignable to the type the
+// service was registered with, otherwise injecting it panics.
+type ResolveInterceptor interface {
+	Intercept(ctx context.Context, info ResolveInfo, next func(ctx context.Context) (any, error)) (any, error)
+}
+
+// ResolveInterceptorFunc adapts a function to the ResolveInterceptor interface.
+type ResolveInterceptorFunc func(ctx context.Context, info ResolveInfo, next func(ctx context.Context) (any, error)) (any, error)
+
+func (f ResolveInterceptorFunc) Intercept(ctx context.Context, info ResolveInfo, next func(ctx context.Context) (any, error)) (any, error) {
+	return f(ctx, info, next)
+}
+
+// AddResolveInterceptor registers an interceptor wrapping every resolution.
+//
+// Interceptors are called in registration order: the first registered interceptor is
+// the outermost one. They must be added before Build.
+//
+// Example:
+//
+//	c.AddResolveInterceptor(ResolveInterceptorFunc(func(ctx context.Context, info ResolveInfo, next func(context.Context) (any, error)) (any, error) {
+//		begin := time.Now()
+//		instance, err := next(ctx)
+//		log.Printf("resolved %s (%s) in %s", info.Name, info.Lifetime, time.Since(begin))
+//		return instance, err
+//	}))
+func (c *Container) AddResolveInterceptor(interceptor ResolveInterceptor) {
+	if c.built {
+		panic(fmt.Errorf("%w: Cannot add interceptors after Build()", ErrContainerAlreadyBuilt))
+	}
+	c.interceptors = append(c.interceptors, interceptor)
+}
+
+// intercept calls next through all interceptors of the container.
+func (c *Container) intercept(ctx context.Context, info ResolveInfo, next func(ctx context.Context) (any, error)) (any, error) {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context) (any, error) {
+			return interceptor.Intercept(ctx, info, inner)
+		}
+	}
+	return next(ctx)
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestResolveInterceptorObservesDependencies(t *testing.T) {
+	c := GetContainer()
+	var mu sync.Mutex
+	var resolved []string
+	c.AddResolveInterceptor(ResolveInterceptorFunc(func(ctx context.Context, info ResolveInfo, next func(context.Context) (any, error)) (any, error) {
+		instance, err := next(ctx)
+		mu.Lock()
+		resolved = append(resolved, info.Name+" "+info.Lifetime.String())
+		mu.Unlock()
+		return instance, err
+	}))
+	c.Build()
+
+	if _, err := RequireService[BInterface](c); err != nil {
+		t.Fatalf("Failed to resolve B: %v", err)
+	}
+	expected := []string{
+		"container.Counter Singleton",
+		"container.A Transient",
+		"container.Counter Singleton",
+		"container.B Singleton",
+	}
+	if !slices.Equal(resolved, expected) {
+		t.Errorf("Expected resolutions %v, got %v", expected, resolved)
+	}
+}
+
+func TestResolveInterceptorOrderAndReplacement(t *testing.T) {
+	c := &Container{}
+	AddValue(c, &Config{Host: "original"})
+	var order []string
+	c.AddResolveInterceptor(ResolveInterceptorFunc(func(ctx context.Context, info ResolveInfo, next func(context.Context) (any, error)) (any, error) {
+		order = append(order, "outer")
+		return next(ctx)
+	}))
+	c.AddResolveInterceptor(ResolveInterceptorFunc(func(ctx context.Context, info ResolveInfo, next func(context.Context) (any, error)) (any, error) {
+		order = append(order, "inner")
+		if _, err := next(ctx); err != nil {
+			return nil, err
+		}
+		return &Config{Host: "replaced"}, nil
+	}))
+	c.Build()
+
+	cfg, err := RequireService[*Config](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve config: %v", err)
+	}
+	if cfg.Host != "replaced" {
+		t.Errorf("Expected replaced instance, got %+v", cfg)
+	}
+	if !slices.Equal(order, []string{"outer", "inner"}) {
+		t.Errorf("Expected outer interceptor first, got %v", order)
+	}
+}
+
+func TestResolveInterceptorError(t *testing.T) {
+	c := &Container{}
+	AddTransientWithoutInterface[Counter](c)
+	denied := errors.New("denied by policy")
+	c.AddResolveInterceptor(ResolveInterceptorFunc(func(ctx context.Context, info ResolveInfo, next func(context.Context) (any, error)) (any, error) {
+		return nil, denied
+	}))
+	c.Build()
+
+	_, err := RequireServicePtr[Counter](c)
+	var resolutionErr *ResolutionError
+	if !errors.Is(err, denied) || !errors.As(err, &resolutionErr) {
+		t.Errorf("Expected policy error in ResolutionError, got %v", err)
+	}
+}
+
+func TestAddResolveInterceptorAfterBuild(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !errors.Is(r.(error), ErrContainerAlreadyBuilt) {
+			t.Errorf("Expected ErrContainerAlreadyBuilt panic, got %v", r)
+		}
+	}()
+	c := BuildContainer()
+	c.AddResolveInterceptor(ResolveInterceptorFunc(nil))
+}
@@ -0,0 +1,160 @@
+package container
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LifecycleListener observes hosted services while they are started and stopped.
+//
+// Listeners are registered with Container.AddLifecycleListener and are called
+// synchronously from StartAsync, StartParallelAsync and StopAsync. When services are
+// started in parallel, calls for different services may happen concurrently.
+type LifecycleListener interface {
+	// OnStarting is called before Start of the hosted service.
+	OnStarting(name string)
+	// OnStarted is called after Start returned, with its duration and error.
+	OnStarted(name string, duration time.Duration, err error)
+	// OnStopping is called before Stop of the hosted service.
+	OnStopping(name string)
+	// OnStopped is called after Stop returned, with its duration and error.
+	OnStopped(name string, duration time.Duration, err error)
+}
+
+// IHostApplicationLifetime allows services to observe application lifetime events.
+//
+// It is registered in every container during Build and can be injected into Init
+// methods like any other interface dependency.
+type IHostApplicationLifetime interface {
+	// ApplicationStarted is closed when all hosted services have started.
+	ApplicationStarted() <-chan struct{}
+	// ApplicationStopping is closed when StopAsync begins stopping hosted services.
+	ApplicationStopping() <-chan struct{}
+	// ApplicationStopped is closed when StopAsync has stopped all hosted services.
+	ApplicationStopped() <-chan struct{}
+	// StopApplication requests the shutdown of the application by stopping all hosted services.
+	StopApplication()
+}
+
+type applicationLifetime struct {
+	container   *Container
+	started     chan struct{}
+	stopping    chan struct{}
+	stopped     chan struct{}
+	startedOnce sync.Once
+	stopOnce    sync.Once
+	stoppedOnce sync.Once
+	stopCall    sync.Once
+	stopErr     error // result of the first StopAsync, set before stopped is closed
+}
+
+func newApplicationLifetime(c *Container) *applicationLifetime {
+	return &applicationLifetime{
+		container: c,
+		started:   make(chan struct{}),
+		stopping:  make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+}
+
+func (l *applicationLifetime) ApplicationStarted() <-chan struct{}  { return l.started }
+func (l *applicationLifetime) ApplicationStopping() <-chan struct{} { return l.stopping }
+func (l *applicationLifetime) ApplicationStopped() <-chan struct{}  { return l.stopped }
+
+func (l *applicationLifetime) StopApplication() {
+	go func() { _ = l.container.StopAsync(context.Background()) }()
+}
+
+// stop stops the hosted services of the container on the first call. Later calls
+// wait until the first one is done, or until ctx is done, and return its result.
+func (l *applicationLifetime) stop(ctx context.Context) error {
+	first := false
+	l.stopCall.Do(func() { first = true })
+	if first {
+		defer l.notifyStopped()
+		l.stopErr = l.container.stopHostedServices(ctx)
+		return l.stopErr
+	}
+	select {
+	case <-l.stopped:
+		return l.stopErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closed reports whether ch, one of the channels of an IHostApplicationLifetime, is closed.
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *applicationLifetime) notifyStarted()  { l.startedOnce.Do(func() { close(l.started) }) }
+func (l *applicationLifetime) notifyStopping() { l.stopOnce.Do(func() { close(l.stopping) }) }
+func (l *applicationLifetime) notifyStopped()  { l.stoppedOnce.Do(func() { close(l.stopped) }) }
+
+// AddLifecycleListener registers a listener that is notified when hosted services
+// are started and stopped.
+//
+// Listeners should be added before StartAsync is called.
+func (c *Container) AddLifecycleListener(listener LifecycleListener) {
+	c.lifecycleListeners = append(c.lifecycleListeners, listener)
+}
+
+// Lifetime returns the application lifetime of the container.
+func (c *Container) Lifetime() IHostApplicationLifetime { return c.appLifetime() }
+
+func (c *Container) appLifetime() *applicationLifetime {
+	if c.lifetime == nil {
+		c.lifetime = newApplicationLifetime(c)
+	}
+	return c.lifetime
+}
+
+func (c *Container) startHostedService(ctx context.Context, i int) error {
+	entry := c.hostedServices[i]
+	name := entry.site.Name()
+	for _, l := range c.lifecycleListeners {
+		l.OnStarting(name)
+	}
+	begin := time.Now()
+	err := callWithTimeout(ctx, entry.site.Options().startTimeout, "starting", name, entry.service.Start)
+	duration := time.Since(begin)
+	if err != nil {
+		c.log(slog.LevelError, "hosted service failed to start", slog.String("service", name),
+			slog.Duration("duration", duration), slog.Any("error", err))
+	} else {
+		c.log(slog.LevelInfo, "hosted service started", slog.String("service", name), slog.Duration("duration", duration))
+	}
+	for _, l := range c.lifecycleListeners {
+		l.OnStarted(name, duration, err)
+	}
+	return err
+}
+
+func (c *Container) stopHostedService(ctx context.Context, i int) error {
+	entry := c.hostedServices[i]
+	name := entry.site.Name()
+	for _, l := range c.lifecycleListeners {
+		l.OnStopping(name)
+	}
+	begin := time.Now()
+	err := callWithTimeout(ctx, entry.site.Options().stopTimeout, "stopping", name, entry.service.Stop)
+	duration := time.Since(begin)
+	if err != nil {
+		c.log(slog.LevelError, "hosted service failed to stop", slog.String("service", name),
+			slog.Duration("duration", duration), slog.Any("error", err))
+	} else {
+		c.log(slog.LevelInfo, "hosted service stopped", slog.String("service", name), slog.Duration("duration", duration))
+	}
+	for _, l := range c.lifecycleListeners {
+		l.OnStopped(name, duration, err)
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingListener struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingListener) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+func (r *recordingListener) OnStarting(name string) { r.record("starting " + name) }
+func (r *recordingListener) OnStarted(name string, duration time.Duration, err error) {
+	r.record("started " + name)
+}
+func (r *recordingListener) OnStopping(name string) { r.record("stopping " + name) }
+func (r *recordingListener) OnStopped(name string, duration time.Duration, err error) {
+	if err != nil {
+		r.record("failed " + name)
+		return
+	}
+	r.record("stopped " + name)
+}
+
+func TestLifecycleListener(t *testing.T) {
+	c := &Container{}
+	AddHostedService[MyHostedService](c)
+	AddHostedService[FailingStopService](c)
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+	c.Build()
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	_ = c.StopAsync(ctx)
+
+	expected := []string{
+		"starting container.MyHostedService",
+		"started container.MyHostedService",
+		"starting container.FailingStopService",
+		"started container.FailingStopService",
+		"stopping container.FailingStopService",
+		"failed container.FailingStopService",
+		"stopping container.MyHostedService",
+		"stopped container.MyHostedService",
+	}
+	if len(listener.events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, listener.events)
+	}
+	for i, event := range expected {
+		if listener.events[i] != event {
+			t.Errorf("Event mismatch at %d: expected %s, got %s", i, event, listener.events[i])
+		}
+	}
+}
+
+type LifetimeAwareService struct {
+	lifetime IHostApplicationLifetime
+}
+
+func (l *LifetimeAwareService) Init(lifetime IHostApplicationLifetime) error {
+	l.lifetime = lifetime
+	return nil
+}
+
+func TestApplicationLifetime(t *testing.T) {
+	c := &Container{}
+	AddHostedService[MyHostedService](c)
+	AddSingletonWithoutInterface[LifetimeAwareService](c)
+	c.Build()
+
+	svc, err := RequireServicePtr[LifetimeAwareService](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve LifetimeAwareService: %v", err)
+	}
+	lifetime := svc.lifetime
+	if lifetime != c.Lifetime() {
+		t.Errorf("Expected injected lifetime to be the container lifetime")
+	}
+	if closed(lifetime.ApplicationStarted()) {
+		t.Errorf("ApplicationStarted should not be closed before StartAsync")
+	}
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	if !closed(lifetime.ApplicationStarted()) {
+		t.Errorf("ApplicationStarted should be closed after StartAsync")
+	}
+	if closed(lifetime.ApplicationStopping()) {
+		t.Errorf("ApplicationStopping should not be closed before StopAsync")
+	}
+
+	lifetime.StopApplication()
+	select {
+	case <-lifetime.ApplicationStopped():
+	case <-time.After(time.Second):
+		t.Fatalf("ApplicationStopped was not closed after StopApplication")
+	}
+	if !closed(lifetime.ApplicationStopping()) {
+		t.Errorf("ApplicationStopping should be closed after StopApplication")
+	}
+
+	hosted, _ := RequireServicePtr[MyHostedService](c)
+	if !hosted.stopped {
+		t.Errorf("Hosted service should be stopped by StopApplication")
+	}
+}
+
+type SlowStopService struct{ stops atomic.Int32 }
+
+func (s *SlowStopService) Init() error                     { return nil }
+func (s *SlowStopService) Start(ctx context.Context) error { return nil }
+func (s *SlowStopService) Stop(ctx context.Context) error {
+	s.stops.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func TestStopApplicationAndStopAsyncStopOnce(t *testing.T) {
+	c := &Container{}
+	AddHostedService[SlowStopService](c)
+	AddHostedService[CountingHostedService](c)
+	c.Build()
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	c.Lifetime().StopApplication()
+	if err := c.StopAsync(ctx); err != nil {
+		t.Fatalf("StopAsync failed: %v", err)
+	}
+	if !closed(c.Lifetime().ApplicationStopped()) {
+		t.Errorf("ApplicationStopped should be closed when StopAsync returns")
+	}
+	if err := c.StopAsync(ctx); err != nil {
+		t.Errorf("Expected a later StopAsync to return the first result, got %v", err)
+	}
+
+	slow, _ := RequireServicePtr[SlowStopService](c)
+	counting, _ := RequireServicePtr[CountingHostedService](c)
+	if slow.stops.Load() != 1 || counting.stopCount != 1 {
+		t.Errorf("Expected each service to be stopped once, got %d and %d", slow.stops.Load(), counting.stopCount)
+	}
+}
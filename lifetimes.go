@@ -0,0 +1,118 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+type lifetimePair struct {
+	consumer, dependency lifetime
+}
+
+// LifetimeRules is the lifetime compatibility matrix of a container: it tells which
+// lifetimes a service may depend on.
+//
+// Rules are checked by Build for direct dependencies and for dependencies reached
+// through Transient intermediates, since a transient constructed for a longer-lived
+// service lives as long as that service.
+type LifetimeRules struct {
+	forbidden map[lifetimePair]bool
+}
+
+// DefaultLifetimeRules forbids Singleton and HostedService services to depend on Scoped
+// and TenantSingleton services, and TenantSingleton services to depend on Scoped services.
+func DefaultLifetimeRules() LifetimeRules {
+	return LifetimeRules{}.
+		Forbid(Singleton, Scoped).
+		Forbid(HostedService, Scoped).
+		Forbid(Singleton, TenantSingleton).
+		Forbid(HostedService, TenantSingleton).
+		Forbid(TenantSingleton, Scoped)
+}
+
+// StrictLifetimeRules extends DefaultLifetimeRules by also forbidding Singleton,
+// HostedService, TenantSingleton and Scoped services to depend on Transient services,
+// which would turn the transient into a de-facto singleton or scoped service.
+func StrictLifetimeRules() LifetimeRules {
+	return DefaultLifetimeRules().
+		Forbid(Singleton, Transient).
+		Forbid(HostedService, Transient).
+		Forbid(TenantSingleton, Transient).
+		Forbid(Scoped, Transient)
+}
+
+// Forbid returns a copy of the rules where services of the consumer lifetime
+// may not depend on services of the dependency lifetime.
+func (r LifetimeRules) Forbid(consumer, dependency lifetime) LifetimeRules {
+	forbidden := maps.Clone(r.forbidden)
+	if forbidden == nil {
+		forbidden = make(map[lifetimePair]bool)
+	}
+	forbidden[lifetimePair{consumer, dependency}] = true
+	return LifetimeRules{forbidden: forbidden}
+}
+
+// Allow returns a copy of the rules where services of the consumer lifetime
+// may depend on services of the dependency lifetime.
+func (r LifetimeRules) Allow(consumer, dependency lifetime) LifetimeRules {
+	forbidden := maps.Clone(r.forbidden)
+	delete(forbidden, lifetimePair{consumer, dependency})
+	return LifetimeRules{forbidden: forbidden}
+}
+
+// Allows reports whether services of the consumer lifetime may depend on services
+// of the dependency lifetime.
+func (r LifetimeRules) Allows(consumer, dependency lifetime) bool {
+	return !r.forbidden[lifetimePair{consumer, dependency}]
+}
+
+// SetLifetimeRules sets the lifetime compatibility matrix checked by Build.
+// DefaultLifetimeRules are used when no rules are set.
+//
+// Example:
+//
+//	c.SetLifetimeRules(StrictLifetimeRules())
+func (c *Container) SetLifetimeRules(rules LifetimeRules) {
+	c.lifetimeRules = &rules
+}
+
+func (c *Container) rules() LifetimeRules {
+	if c.lifetimeRules == nil {
+		return DefaultLifetimeRules()
+	}
+	return *c.lifetimeRules
+}
+
+// checkLifetimes returns a *CaptiveDependencyError for every service capturing a
+// dependency with a forbidden lifetime, in service name order.
+func (c *Container) checkLifetimes() error {
+	rules := c.rules()
+	var errs []error
+	for _, consumer := range c.uniqueSites() {
+		visited := map[callSiteInterface]bool{consumer: true}
+		var walk func(site callSiteInterface, path []ResolutionStep)
+		walk = func(site callSiteInterface, path []ResolutionStep) {
+			for _, depName := range site.Deps() {
+				dep, ok := c.lookupFor(site, depName)
+				if !ok || visited[dep] {
+					continue
+				}
+				visited[dep] = true
+				depPath := append(path[:len(path):len(path)], ResolutionStep{Name: dep.Name(), Lifetime: dep.Lifetime()})
+				if !rules.Allows(consumer.Lifetime(), dep.Lifetime()) {
+					errs = append(errs, &CaptiveDependencyError{Path: depPath})
+					continue
+				}
+				if dep.Lifetime() == Transient {
+					walk(dep, depPath)
+				}
+			}
+		}
+		walk(consumer, []ResolutionStep{{Name: consumer.Name(), Lifetime: consumer.Lifetime()}})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid service lifetimes: %w", errors.Join(errs...))
+}
@@ -0,0 +1,80 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type TransientOverScoped struct{}
+
+func (t *TransientOverScoped) Init(c *C) error { return nil }
+
+type SingletonOverTransient struct{}
+
+func (s *SingletonOverTransient) Init(t *TransientOverScoped) error { return nil }
+
+func buildPanic(c *Container) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	c.Build()
+	return nil
+}
+
+func TestTransitiveCaptiveDependency(t *testing.T) {
+	c := GetContainer()
+	AddTransientWithoutInterface[TransientOverScoped](c)
+	AddSingletonWithoutInterface[SingletonOverTransient](c)
+
+	err := buildPanic(c)
+	var captiveErr *CaptiveDependencyError
+	if !errors.As(err, &captiveErr) {
+		t.Fatalf("Expected CaptiveDependencyError, got %v", err)
+	}
+	expected := "container.SingletonOverTransient (Singleton) -> container.TransientOverScoped (Transient) -> container.C (Scoped)"
+	if !strings.HasSuffix(captiveErr.Error(), ": "+expected) {
+		t.Errorf("Expected path %q, got %q", expected, captiveErr.Error())
+	}
+	if len(captiveErr.Path) != 3 || captiveErr.Path[2].Lifetime != Scoped {
+		t.Errorf("Unexpected path %+v", captiveErr.Path)
+	}
+}
+
+func TestStrictLifetimeRules(t *testing.T) {
+	c := GetContainer()
+	c.SetLifetimeRules(StrictLifetimeRules())
+
+	err := buildPanic(c)
+	if !errors.Is(err, ErrCaptiveDependency) {
+		t.Fatalf("Expected ErrCaptiveDependency, got %v", err)
+	}
+	// B (Singleton) and C (Scoped) both capture the transient A
+	for _, expected := range []string{
+		"Singleton container.B captures Transient container.A",
+		"Scoped container.C captures Transient container.A",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %v", expected, err)
+		}
+	}
+}
+
+func TestCustomLifetimeRules(t *testing.T) {
+	rules := StrictLifetimeRules().Allow(Scoped, Transient)
+	if !rules.Allows(Scoped, Transient) || rules.Allows(Singleton, Transient) {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+	if !StrictLifetimeRules().Allows(Transient, Transient) || DefaultLifetimeRules().Allows(Singleton, Scoped) {
+		t.Errorf("Unexpected predefined rules")
+	}
+
+	c := GetContainer()
+	c.SetLifetimeRules(DefaultLifetimeRules().Allow(Singleton, Scoped))
+	AddSingleton[DInterface, D](c)
+	if err := buildPanic(c); err != nil {
+		t.Errorf("Expected Singleton to Scoped to be allowed, got %v", err)
+	}
+}
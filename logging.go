@@ -0,0 +1,35 @@
+package container
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetLogger sets the logger used by the container to emit structured records.
+//
+// The container is silent when no logger is set. Records are emitted at these levels:
+//   - Debug: registration, Build validation, construction and cache hits of services,
+//     scope creation and disposal
+//   - Info: hosted services started and stopped
+//   - Warn: failed constructions
+//   - Error: failed Build validation and hosted services failing to start or stop
+//
+// SetLogger should be called before services are registered to log their registration.
+func (c *Container) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+func (c *Container) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if c == nil || c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (c *Container) logEnabled(level slog.Level) bool {
+	return c != nil && c.logger != nil && c.logger.Enabled(context.Background(), level)
+}
+
+func serviceAttrs(name string, lifetime lifetime) []slog.Attr {
+	return []slog.Attr{slog.String("service", name), slog.String("lifetime", lifetime.String())}
+}
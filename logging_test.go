@@ -0,0 +1,155 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type logRecord struct {
+	Level    string `json:"level"`
+	Msg      string `json:"msg"`
+	Service  string `json:"service"`
+	Lifetime string `json:"lifetime"`
+	Scope    uint64 `json:"scope"`
+}
+
+func parseLogRecords(t *testing.T, buf *bytes.Buffer) []logRecord {
+	t.Helper()
+	var records []logRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r logRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Invalid log record %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func hasLogRecord(records []logRecord, msg, service string) bool {
+	for _, r := range records {
+		if r.Msg == msg && r.Service == service {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoggerRecordsRegistrationAndConstruction(t *testing.T) {
+	var buf bytes.Buffer
+	c := &Container{}
+	c.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	AddSingletonWithoutInterface[Counter](c)
+	AddScopedWithoutInterface[A](c)
+	c.Build()
+
+	scope := c.CreateScope()
+	if _, err := RequireServiceForScope[A](scope); err != nil {
+		t.Fatalf("Failed to resolve A: %v", err)
+	}
+	if _, err := RequireServiceForScope[A](scope); err != nil {
+		t.Fatalf("Failed to resolve A: %v", err)
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("Failed to close scope: %v", err)
+	}
+
+	records := parseLogRecords(t, &buf)
+	for _, expected := range []struct{ msg, service string }{
+		{"service registered", "container.Counter"},
+		{"service registered", "container.A"},
+		{"container built", ""},
+		{"scope created", ""},
+		{"service constructed", "container.Counter"},
+		{"service constructed", "container.A"},
+		{"service resolved from cache", "container.A"},
+		{"scope closed", ""},
+	} {
+		if !hasLogRecord(records, expected.msg, expected.service) {
+			t.Errorf("Expected record %q for %q, got %+v", expected.msg, expected.service, records)
+		}
+	}
+	for _, r := range records {
+		if r.Msg == "service constructed" && r.Service == "container.A" && (r.Lifetime != "Scoped" || r.Scope != scope.ID()) {
+			t.Errorf("Expected Scoped construction in scope %d, got %+v", scope.ID(), r)
+		}
+	}
+}
+
+func TestLoggerIsQuietAtInfoLevel(t *testing.T) {
+	var buf bytes.Buffer
+	c := &Container{}
+	c.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	AddSingletonWithoutInterface[Counter](c)
+	AddTransientWithoutInterface[A](c)
+	AddTransientWithoutInterface[ServiceWithFailingInit](c)
+	c.Build()
+
+	if _, err := RequireService[A](c); err != nil {
+		t.Fatalf("Failed to resolve A: %v", err)
+	}
+	_, _ = RequireService[ServiceWithFailingInit](c)
+
+	records := parseLogRecords(t, &buf)
+	if len(records) != 1 || records[0].Level != "WARN" || records[0].Msg != "service construction failed" {
+		t.Errorf("Expected only the construction failure, got %+v", records)
+	}
+}
+
+var errClose = errors.New("close failed")
+
+type CloseLog struct{ Closed []string }
+
+type FirstClosable struct{ log *CloseLog }
+
+func (s *FirstClosable) Init(log *CloseLog) error { s.log = log; return nil }
+func (s *FirstClosable) Close() error {
+	s.log.Closed = append(s.log.Closed, "first")
+	return nil
+}
+
+type SecondClosable struct{ log *CloseLog }
+
+func (s *SecondClosable) Init(log *CloseLog) error { s.log = log; return nil }
+func (s *SecondClosable) Close() error {
+	s.log.Closed = append(s.log.Closed, "second")
+	return errClose
+}
+
+func TestScopeCloseDisposesInReverseOrder(t *testing.T) {
+	closeLog := &CloseLog{}
+	c := &Container{}
+	AddValue(c, closeLog)
+	AddScopedWithoutInterface[FirstClosable](c)
+	AddScopedWithoutInterface[SecondClosable](c)
+	c.Build()
+
+	scope := c.CreateScope()
+	if _, err := RequireServiceForScope[FirstClosable](scope); err != nil {
+		t.Fatalf("Failed to resolve first: %v", err)
+	}
+	if _, err := RequireServiceForScope[SecondClosable](scope); err != nil {
+		t.Fatalf("Failed to resolve second: %v", err)
+	}
+
+	err := scope.Close()
+	if !errors.Is(err, errClose) {
+		t.Errorf("Expected close error, got %v", err)
+	}
+	if strings.Join(closeLog.Closed, ",") != "second,first" {
+		t.Errorf("Expected reverse close order, got %v", closeLog.Closed)
+	}
+	if err := scope.Close(); err != nil {
+		t.Errorf("Expected second Close to be a no-op, got %v", err)
+	}
+	if _, err := RequireServiceForScope[FirstClosable](scope); !errors.Is(err, ErrScopeClosed) {
+		t.Errorf("Expected ErrScopeClosed, got %v", err)
+	}
+}
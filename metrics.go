@@ -0,0 +1,251 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServiceEvent describes the construction of a service or its resolution from a cache.
+type ServiceEvent struct {
+	Name     string
+	Lifetime lifetime
+	// Duration of the Init call and validation, excluding the resolution of the
+	// dependencies. Zero for cache hits.
+	Duration time.Duration
+	// Err is the construction error, nil on success.
+	Err error
+}
+
+// MetricsCollector receives events about service constructions and cache hits.
+//
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	// ObserveConstruction is called after each call of the Init method of a service,
+	// successful or not. It is not called when Init was not reached because a
+	// dependency failed or the resolution was aborted.
+	ObserveConstruction(event ServiceEvent)
+	// ObserveCacheHit is called when a Singleton, HostedService, TenantSingleton or
+	// Scoped service is resolved from an already constructed instance.
+	ObserveCacheHit(event ServiceEvent)
+}
+
+// SetMetrics sets the collector of construction metrics. Metrics are not collected by default.
+//
+// Use NewMetrics for the in-memory implementation.
+func (c *Container) SetMetrics(collector MetricsCollector) {
+	c.metrics = collector
+}
+
+func (c *Container) observeConstruction(name string, lifetime lifetime, duration time.Duration, err error) {
+	if c == nil || c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveConstruction(ServiceEvent{Name: name, Lifetime: lifetime, Duration: duration, Err: err})
+}
+
+func (c *Container) observeCacheHit(name string, lifetime lifetime) {
+	if c == nil || c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveCacheHit(ServiceEvent{Name: name, Lifetime: lifetime})
+}
+
+// DefaultLatencyBuckets are the upper bounds of the Init latency histogram of NewMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// Metrics is the in-memory MetricsCollector.
+//
+// It serves its metrics in the Prometheus text exposition format over HTTP.
+type Metrics struct {
+	mu       sync.Mutex
+	buckets  []time.Duration
+	services map[string]*serviceMetrics
+}
+
+type serviceMetrics struct {
+	lifetime      lifetime
+	constructions uint64
+	failures      uint64
+	cacheHits     uint64
+	latencySum    time.Duration
+	latencyCount  uint64
+	bucketCounts  []uint64 // not cumulative
+}
+
+// NewMetrics creates an in-memory collector with the given latency histogram buckets.
+// DefaultLatencyBuckets are used when none are given.
+func NewMetrics(buckets ...time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Metrics{buckets: slices.Compact(buckets), services: make(map[string]*serviceMetrics)}
+}
+
+func (m *Metrics) service(event ServiceEvent) *serviceMetrics {
+	s, ok := m.services[event.Name]
+	if !ok {
+		s = &serviceMetrics{lifetime: event.Lifetime, bucketCounts: make([]uint64, len(m.buckets))}
+		m.services[event.Name] = s
+	}
+	return s
+}
+
+// ObserveConstruction implements MetricsCollector.
+func (m *Metrics) ObserveConstruction(event ServiceEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.service(event)
+	if event.Err != nil {
+		s.failures++
+	} else {
+		s.constructions++
+	}
+	s.latencySum += event.Duration
+	s.latencyCount++
+	if i, _ := slices.BinarySearch(m.buckets, event.Duration); i < len(m.buckets) {
+		s.bucketCounts[i]++
+	}
+}
+
+// ObserveCacheHit implements MetricsCollector.
+func (m *Metrics) ObserveCacheHit(event ServiceEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.service(event).cacheHits++
+}
+
+// HistogramBucket is a cumulative bucket of a latency histogram.
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// LatencyHistogram is the distribution of Init latencies of a service.
+// Observations above the last bucket are only counted in Count and Sum.
+type LatencyHistogram struct {
+	Buckets []HistogramBucket
+	Count   uint64
+	Sum     time.Duration
+}
+
+// ServiceMetrics are the metrics of a single service.
+type ServiceMetrics struct {
+	Name     string
+	Lifetime lifetime
+	// Constructions is the number of successful constructions.
+	Constructions uint64
+	// Failures is the number of failed constructions.
+	Failures  uint64
+	CacheHits uint64
+	// Latency of all constructions, successful or not.
+	Latency LatencyHistogram
+}
+
+// MetricsSnapshot is a point-in-time copy of the collected metrics, sorted by service name.
+type MetricsSnapshot struct {
+	Services []ServiceMetrics
+}
+
+// Service returns the metrics of the named service.
+func (s MetricsSnapshot) Service(name string) (ServiceMetrics, bool) {
+	i, ok := slices.BinarySearchFunc(s.Services, name, func(m ServiceMetrics, name string) int {
+		return strings.Compare(m.Name, name)
+	})
+	if !ok {
+		return ServiceMetrics{}, false
+	}
+	return s.Services[i], true
+}
+
+// Snapshot returns a copy of the collected metrics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := MetricsSnapshot{Services: make([]ServiceMetrics, 0, len(m.services))}
+	for name, s := range m.services {
+		histogram := LatencyHistogram{Buckets: make([]HistogramBucket, len(m.buckets)), Count: s.latencyCount, Sum: s.latencySum}
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.bucketCounts[i]
+			histogram.Buckets[i] = HistogramBucket{UpperBound: bound, Count: cumulative}
+		}
+		snapshot.Services = append(snapshot.Services, ServiceMetrics{
+			Name:          name,
+			Lifetime:      s.lifetime,
+			Constructions: s.constructions,
+			Failures:      s.failures,
+			CacheHits:     s.cacheHits,
+			Latency:       histogram,
+		})
+	}
+	slices.SortFunc(snapshot.Services, func(a, b ServiceMetrics) int { return strings.Compare(a.Name, b.Name) })
+	return snapshot
+}
+
+// WritePrometheus writes the snapshot in the Prometheus text exposition format.
+func (s MetricsSnapshot) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+	counters := []struct {
+		name, help string
+		value      func(ServiceMetrics) uint64
+	}{
+		{"tinydi_service_constructions_total", "Number of successful service constructions.",
+			func(m ServiceMetrics) uint64 { return m.Constructions }},
+		{"tinydi_service_construction_failures_total", "Number of failed service constructions.",
+			func(m ServiceMetrics) uint64 { return m.Failures }},
+		{"tinydi_service_cache_hits_total", "Number of resolutions served from an already constructed instance.",
+			func(m ServiceMetrics) uint64 { return m.CacheHits }},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		for _, m := range s.Services {
+			fmt.Fprintf(&b, "%s{%s} %d\n", counter.name, serviceLabels(m), counter.value(m))
+		}
+	}
+
+	const histogram = "tinydi_service_construction_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Duration of service Init calls, excluding dependencies.\n# TYPE %s histogram\n", histogram, histogram)
+	for _, m := range s.Services {
+		labels := serviceLabels(m)
+		for _, bucket := range m.Latency.Buckets {
+			fmt.Fprintf(&b, "%s_bucket{%s,le=%q} %d\n", histogram, labels, formatSeconds(bucket.UpperBound), bucket.Count)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", histogram, labels, m.Latency.Count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", histogram, labels, formatSeconds(m.Latency.Sum))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", histogram, labels, m.Latency.Count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP writes a snapshot of the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.Snapshot().WritePrometheus(w)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func serviceLabels(m ServiceMetrics) string {
+	return fmt.Sprintf(`service="%s",lifetime="%s"`, labelValueEscaper.Replace(m.Name), m.Lifetime)
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
@@ -0,0 +1,141 @@
+package container
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsCountConstructionsAndCacheHits(t *testing.T) {
+	metrics := NewMetrics()
+	c := &Container{}
+	c.SetMetrics(metrics)
+	AddSingletonWithoutInterface[Counter](c)
+	AddTransientWithoutInterface[A](c)
+	AddTransientWithoutInterface[ServiceWithFailingInit](c)
+	c.Build()
+
+	for range 3 {
+		if _, err := RequireService[A](c); err != nil {
+			t.Fatalf("Failed to resolve A: %v", err)
+		}
+	}
+	if _, err := RequireService[ServiceWithFailingInit](c); err == nil {
+		t.Fatal("Expected construction error")
+	}
+
+	snapshot := metrics.Snapshot()
+	a, ok := snapshot.Service("container.A")
+	if !ok || a.Constructions != 3 || a.CacheHits != 0 || a.Latency.Count != 3 || a.Lifetime != Transient {
+		t.Errorf("Unexpected metrics for A: %+v", a)
+	}
+	counter, ok := snapshot.Service("container.Counter")
+	if !ok || counter.Constructions != 1 || counter.CacheHits != 2 {
+		t.Errorf("Unexpected metrics for Counter: %+v", counter)
+	}
+	failing, ok := snapshot.Service("container.ServiceWithFailingInit")
+	if !ok || failing.Failures != 1 || failing.Constructions != 0 {
+		t.Errorf("Unexpected metrics for ServiceWithFailingInit: %+v", failing)
+	}
+}
+
+func TestMetricsHistogramBuckets(t *testing.T) {
+	metrics := NewMetrics(time.Second, time.Millisecond)
+	for _, d := range []time.Duration{time.Microsecond, time.Millisecond, 500 * time.Millisecond, 2 * time.Second} {
+		metrics.ObserveConstruction(ServiceEvent{Name: "svc", Lifetime: Transient, Duration: d})
+	}
+	metrics.ObserveConstruction(ServiceEvent{Name: "svc", Lifetime: Transient, Err: errors.New("boom")})
+
+	svc, _ := metrics.Snapshot().Service("svc")
+	expected := []HistogramBucket{{time.Millisecond, 3}, {time.Second, 4}}
+	if len(svc.Latency.Buckets) != len(expected) {
+		t.Fatalf("Expected buckets %v, got %v", expected, svc.Latency.Buckets)
+	}
+	for i, bucket := range expected {
+		if svc.Latency.Buckets[i] != bucket {
+			t.Errorf("Expected bucket %v, got %v", bucket, svc.Latency.Buckets[i])
+		}
+	}
+	if svc.Latency.Count != 5 || svc.Constructions != 4 || svc.Failures != 1 {
+		t.Errorf("Unexpected metrics %+v", svc)
+	}
+}
+
+func TestMetricsServePrometheus(t *testing.T) {
+	metrics := NewMetrics(time.Millisecond)
+	metrics.ObserveConstruction(ServiceEvent{Name: `svc"1`, Lifetime: Singleton, Duration: 2 * time.Millisecond})
+	metrics.ObserveCacheHit(ServiceEvent{Name: `svc"1`, Lifetime: Singleton})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	for _, line := range []string{
+		"# TYPE tinydi_service_constructions_total counter",
+		`tinydi_service_constructions_total{service="svc\"1",lifetime="Singleton"} 1`,
+		`tinydi_service_cache_hits_total{service="svc\"1",lifetime="Singleton"} 1`,
+		"# TYPE tinydi_service_construction_duration_seconds histogram",
+		`tinydi_service_construction_duration_seconds_bucket{service="svc\"1",lifetime="Singleton",le="0.001"} 0`,
+		`tinydi_service_construction_duration_seconds_bucket{service="svc\"1",lifetime="Singleton",le="+Inf"} 1`,
+		`tinydi_service_construction_duration_seconds_sum{service="svc\"1",lifetime="Singleton"} 0.002`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, body)
+		}
+	}
+}
+
+type SlowInit struct{}
+
+func (s *SlowInit) Init() error { time.Sleep(20 * time.Millisecond); return nil }
+
+type DependsOnSlowInit struct{}
+
+func (d *DependsOnSlowInit) Init(s *SlowInit) error { return nil }
+
+func TestMetricsDurationExcludesDependencies(t *testing.T) {
+	metrics := NewMetrics()
+	c := &Container{}
+	c.SetMetrics(metrics)
+	AddTransientWithoutInterface[SlowInit](c)
+	AddTransientWithoutInterface[DependsOnSlowInit](c)
+	c.Build()
+
+	if _, err := RequireService[DependsOnSlowInit](c); err != nil {
+		t.Fatalf("Failed to resolve DependsOnSlowInit: %v", err)
+	}
+	snapshot := metrics.Snapshot()
+	slow, _ := snapshot.Service("container.SlowInit")
+	dependent, _ := snapshot.Service("container.DependsOnSlowInit")
+	if slow.Latency.Sum < 20*time.Millisecond || dependent.Latency.Sum >= 20*time.Millisecond {
+		t.Errorf("Expected the Init of SlowInit to be counted only for SlowInit, got %s and %s",
+			slow.Latency.Sum, dependent.Latency.Sum)
+	}
+}
+
+func TestMetricsIgnoreDependencyFailures(t *testing.T) {
+	metrics := NewMetrics()
+	c := &Container{}
+	c.SetMetrics(metrics)
+	AddTransientWithoutInterface[ServiceWithFailingInit](c)
+	AddTransientWithoutInterface[ResolutionMiddle](c)
+	c.Build()
+
+	if _, err := RequireServicePtr[ResolutionMiddle](c); err == nil {
+		t.Fatal("Expected construction error")
+	}
+	snapshot := metrics.Snapshot()
+	failing, _ := snapshot.Service("container.ServiceWithFailingInit")
+	if failing.Failures != 1 || failing.Latency.Count != 1 {
+		t.Errorf("Expected the failed Init to be observed, got %+v", failing)
+	}
+	if middle, ok := snapshot.Service("container.ResolutionMiddle"); ok && (middle.Failures != 0 || middle.Latency.Count != 0) {
+		t.Errorf("Expected no failure nor latency for the dependent, got %+v", middle)
+	}
+}
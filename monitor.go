@@ -0,0 +1,240 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval of watched files created with an interval of zero.
+const DefaultWatchInterval = time.Second
+
+// WatchableSource is a ConfigSource that can report changes of its content.
+type WatchableSource interface {
+	ConfigSource
+	// Watch calls changed whenever the content of the source differs from the content
+	// it had when it was last loaded or reported changed, until stop is closed.
+	Watch(stop <-chan struct{}, changed func())
+}
+
+type watchedFileSource struct {
+	ConfigSource
+	path     string
+	interval time.Duration
+
+	mu           sync.Mutex
+	lastModified time.Time
+	lastSize     int64
+}
+
+// WatchFile loads configuration from the file at path using decoder and reports
+// changes of the file since it was last loaded, detected by polling its size and
+// modification time every interval.
+func WatchFile(path string, decoder Decoder, interval time.Duration) WatchableSource {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	return &watchedFileSource{ConfigSource: FileSource(path, decoder), path: path, interval: interval}
+}
+
+// WatchJSONFile loads configuration from the JSON file at path and reports its changes.
+// See WatchFile.
+func WatchJSONFile(path string, interval time.Duration) WatchableSource {
+	return WatchFile(path, DecoderFunc(json.Unmarshal), interval)
+}
+
+func (w *watchedFileSource) stat() (time.Time, int64) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
+
+func (w *watchedFileSource) clone() *watchedFileSource {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return &watchedFileSource{
+		ConfigSource: w.ConfigSource,
+		path:         w.path,
+		interval:     w.interval,
+		lastModified: w.lastModified,
+		lastSize:     w.lastSize,
+	}
+}
+
+func (w *watchedFileSource) Load(target any) error {
+	w.mu.Lock()
+	w.lastModified, w.lastSize = w.stat()
+	w.mu.Unlock()
+	return w.ConfigSource.Load(target)
+}
+
+func (w *watchedFileSource) Watch(stop <-chan struct{}, changed func()) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			modified, size := w.stat()
+			w.mu.Lock()
+			isChanged := !modified.Equal(w.lastModified) || size != w.lastSize
+			// Recorded here too, since a reload failing on another source does not load this one.
+			w.lastModified, w.lastSize = modified, size
+			w.mu.Unlock()
+			if isChanged {
+				changed()
+			}
+		}
+	}
+}
+
+// OptionsMonitor provides the current value of a configuration struct that is reloaded
+// when one of its WatchableSource sources changes.
+//
+// Monitors are created with ConfigureMonitor and injected as *OptionsMonitor[T].
+// The container registers every monitor as a hosted service: watching starts with
+// StartAsync and stops with StopAsync.
+type OptionsMonitor[T any] struct {
+	mu        sync.RWMutex
+	current   T
+	lastErr   error
+	sources   []ConfigSource
+	listeners map[int]func(T)
+	nextID    int
+	stop      chan struct{}
+	watchers  sync.WaitGroup
+}
+
+// ConfigureMonitor binds the configuration struct T like Configure and registers
+// a *OptionsMonitor[T] that reloads it whenever a WatchableSource among sources changes.
+//
+// Example:
+//
+//	err := ConfigureMonitor[FeatureFlags](c, WatchJSONFile("flags.json", time.Second))
+func ConfigureMonitor[T any](c *Container, sources ...ConfigSource) error {
+	value, err := bindConfig[T](sources)
+	if err != nil {
+		return err
+	}
+	monitor := &OptionsMonitor[T]{
+		current:   value,
+		sources:   sources,
+		listeners: make(map[int]func(T)),
+	}
+	site := addValue(c, monitor)
+	c.hostedServiceSites = append(c.hostedServiceSites, site)
+	return nil
+}
+
+// cloneValue returns an unstarted monitor with the value of m, without listeners.
+// Watched files are copied, so that both monitors detect their changes.
+func (m *OptionsMonitor[T]) cloneValue() any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sources := make([]ConfigSource, len(m.sources))
+	for i, source := range m.sources {
+		if watched, ok := source.(*watchedFileSource); ok {
+			source = watched.clone()
+		}
+		sources[i] = source
+	}
+	return &OptionsMonitor[T]{
+		current:   m.current,
+		lastErr:   m.lastErr,
+		sources:   sources,
+		listeners: make(map[int]func(T)),
+	}
+}
+
+// CurrentValue returns the latest successfully bound value.
+func (m *OptionsMonitor[T]) CurrentValue() T {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// LastError returns the error of the latest reload, or nil if it succeeded.
+// A failed reload keeps the previous value.
+func (m *OptionsMonitor[T]) LastError() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr
+}
+
+// OnChange registers listener to be called with the new value after every reload that
+// changed the value. The returned function removes the listener.
+func (m *OptionsMonitor[T]) OnChange(listener func(T)) (unsubscribe func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextID
+	m.nextID++
+	m.listeners[id] = listener
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.listeners, id)
+	}
+}
+
+// Reload binds the value again from all sources and notifies listeners if it changed.
+func (m *OptionsMonitor[T]) Reload() error {
+	value, err := bindConfig[T](m.sources)
+	m.mu.Lock()
+	m.lastErr = err
+	if err != nil || reflect.DeepEqual(value, m.current) {
+		m.mu.Unlock()
+		return err
+	}
+	m.current = value
+	listeners := make([]func(T), 0, len(m.listeners))
+	for _, listener := range m.listeners {
+		listeners = append(listeners, listener)
+	}
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(value)
+	}
+	return nil
+}
+
+// Start starts watching the sources of the monitor.
+func (m *OptionsMonitor[T]) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return nil
+	}
+	stop := make(chan struct{})
+	m.stop = stop
+	for _, source := range m.sources {
+		if watchable, ok := source.(WatchableSource); ok {
+			m.watchers.Add(1)
+			go func() {
+				defer m.watchers.Done()
+				watchable.Watch(stop, func() { _ = m.Reload() })
+			}()
+		}
+	}
+	return nil
+}
+
+// Stop stops watching the sources of the monitor and waits for the watchers to exit.
+func (m *OptionsMonitor[T]) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	stop := m.stop
+	m.stop = nil
+	m.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	m.watchers.Wait()
+	return nil
+}
@@ -0,0 +1,125 @@
+package container
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+type FeatureFlags struct {
+	RateLimit int `json:"rateLimit" default:"10"`
+}
+
+type FlagConsumer struct {
+	monitor *OptionsMonitor[FeatureFlags]
+}
+
+func (f *FlagConsumer) Init(monitor *OptionsMonitor[FeatureFlags]) error {
+	f.monitor = monitor
+	return nil
+}
+
+func TestOptionsMonitorReloadsWatchedFile(t *testing.T) {
+	path := writeConfigFile(t, `{"rateLimit": 5}`)
+
+	c := &Container{}
+	if err := ConfigureMonitor[FeatureFlags](c, WatchJSONFile(path, 5*time.Millisecond)); err != nil {
+		t.Fatalf("ConfigureMonitor failed: %v", err)
+	}
+	AddSingletonWithoutInterface[FlagConsumer](c)
+	c.Build()
+
+	consumer, err := RequireServicePtr[FlagConsumer](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve consumer: %v", err)
+	}
+	monitor, _ := RequireService[*OptionsMonitor[FeatureFlags]](c)
+	if consumer.monitor != monitor {
+		t.Errorf("Expected the monitor to be a singleton")
+	}
+	if monitor.CurrentValue().RateLimit != 5 {
+		t.Errorf("Expected rate limit 5, got %d", monitor.CurrentValue().RateLimit)
+	}
+
+	changes := make(chan FeatureFlags, 1)
+	monitor.OnChange(func(flags FeatureFlags) { changes <- flags })
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"rateLimit": 100}`), 0o600); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	select {
+	case flags := <-changes:
+		if flags.RateLimit != 100 {
+			t.Errorf("Expected rate limit 100, got %d", flags.RateLimit)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Change was not notified")
+	}
+	if monitor.CurrentValue().RateLimit != 100 {
+		t.Errorf("Expected current rate limit 100, got %d", monitor.CurrentValue().RateLimit)
+	}
+
+	if err := c.StopAsync(ctx); err != nil {
+		t.Fatalf("StopAsync failed: %v", err)
+	}
+	if monitor.stop != nil {
+		t.Errorf("Monitor should be stopped by StopAsync")
+	}
+}
+
+func TestOptionsMonitorKeepsValueOnInvalidReload(t *testing.T) {
+	path := writeConfigFile(t, `{"rateLimit": 5}`)
+	c := &Container{}
+	if err := ConfigureMonitor[FeatureFlags](c, JSONFile(path)); err != nil {
+		t.Fatalf("ConfigureMonitor failed: %v", err)
+	}
+	c.Build()
+	monitor, _ := RequireService[*OptionsMonitor[FeatureFlags]](c)
+
+	notified := false
+	unsubscribe := monitor.OnChange(func(FeatureFlags) { notified = true })
+
+	_ = os.WriteFile(path, []byte(`{"rateLimit": "fast"}`), 0o600)
+	if err := monitor.Reload(); err == nil || monitor.LastError() == nil {
+		t.Errorf("Expected reload error")
+	}
+	if monitor.CurrentValue().RateLimit != 5 || notified {
+		t.Errorf("Invalid reload should keep the previous value")
+	}
+
+	unsubscribe()
+	_ = os.WriteFile(path, []byte(`{"rateLimit": 7}`), 0o600)
+	if err := monitor.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if monitor.CurrentValue().RateLimit != 7 || notified {
+		t.Errorf("Expected value 7 without notifying the removed listener")
+	}
+}
+
+func TestWatchFileReportsChangeOnce(t *testing.T) {
+	path := writeConfigFile(t, `{"rateLimit": 5}`)
+	source := WatchJSONFile(path, 2*time.Millisecond)
+	var flags FeatureFlags
+	if err := source.Load(&flags); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"rateLimit": 100}`), 0o600); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	// The source is not loaded again, like when a reload fails on another source
+	changes := 0
+	stop := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+	source.Watch(stop, func() { changes++ })
+	if changes != 1 {
+		t.Errorf("Expected the change to be reported once, got %d", changes)
+	}
+}
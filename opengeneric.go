@@ -0,0 +1,184 @@
+package container
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// openGeneric is a registration of every instantiation of a generic struct type.
+type openGeneric struct {
+	family   string
+	lifetime lifetime
+	opts     []Option
+}
+
+// genericFamily returns the generic type t or *t is an instantiation of,
+// e.g. "example.com/app.Repository" for Repository[User].
+func genericFamily(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", false
+	}
+	name, _, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return "", false
+	}
+	return t.PkgPath() + "." + name, true
+}
+
+func addOpenGeneric[T any](c *Container, lifetime lifetime, opts ...Option) {
+	if c.built {
+		panic(fmt.Errorf("%w: Cannot add dependencies after Build()", ErrContainerAlreadyBuilt))
+	}
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("%w: Type %s should be struct type", ErrShouldBeStructType, t))
+	}
+	family, ok := genericFamily(t)
+	if !ok {
+		panic(fmt.Errorf("%w: Type %s should be an instantiation of a generic type, e.g. Repository[any]", ErrNotGenericType, t))
+	}
+	if _, ok := c.openGenerics[family]; ok {
+		panic(fmt.Errorf("%w: Open generic %s already exists in container", ErrTypeAlreadyRegistered, family))
+	}
+	if c.openGenerics == nil {
+		c.openGenerics = make(map[string]openGeneric)
+	}
+	c.openGenerics[family] = openGeneric{family: family, lifetime: lifetime, opts: opts}
+	c.log(slog.LevelDebug, "open generic registered", slog.String("service", family), slog.String("lifetime", lifetime.String()))
+}
+
+// AddOpenGenericSingleton registers every instantiation of the generic struct type of T
+// as a singleton. T is any instantiation of it, e.g. Repository[any] registers
+// Repository[User], Repository[Order] and so on.
+//
+// Go cannot instantiate generic types at runtime, so Build materializes a registration
+// only for the instantiations requested by the Init method of a service or declared
+// with AddEntryPoint, which are then validated like any other registration.
+// Instantiations are constructed through reflection and registered instantiations
+// take precedence over the open generic registration.
+//
+// Example:
+//
+//	type Repository[T any] struct{ db *Database }
+//
+//	func (r *Repository[T]) Init(db *Database) error { r.db = db; return nil }
+//
+//	AddOpenGenericSingleton[Repository[any]](c)
+//	AddTransientWithoutInterface[UserService](c) // Init(users *Repository[User]) error
+func AddOpenGenericSingleton[T any](c *Container, opts ...Option) {
+	addOpenGeneric[T](c, Singleton, opts...)
+}
+
+// AddOpenGenericTransient registers every instantiation of the generic struct type of T
+// as transient. See AddOpenGenericSingleton.
+func AddOpenGenericTransient[T any](c *Container, opts ...Option) {
+	addOpenGeneric[T](c, Transient, opts...)
+}
+
+// AddOpenGenericScoped registers every instantiation of the generic struct type of T
+// as scoped. See AddOpenGenericSingleton.
+func AddOpenGenericScoped[T any](c *Container, opts ...Option) {
+	addOpenGeneric[T](c, Scoped, opts...)
+}
+
+// openGeneric returns the open generic registration of family in c or its ancestors.
+func (c *Container) openGeneric(family string) (openGeneric, bool) {
+	for ; c != nil; c = c.parent {
+		if template, ok := c.openGenerics[family]; ok {
+			return template, true
+		}
+	}
+	return openGeneric{}, false
+}
+
+// materializeOpenGenerics registers a call site for every requested instantiation of
+// an open generic type that is not registered, including instantiations requested by
+// materialized call sites.
+func (c *Container) materializeOpenGenerics() error {
+	hasOpenGenerics := false
+	for ancestor := c; ancestor != nil; ancestor = ancestor.parent {
+		hasOpenGenerics = hasOpenGenerics || len(ancestor.openGenerics) > 0
+	}
+	if !hasOpenGenerics {
+		return nil
+	}
+	for _, t := range c.entryPoints {
+		if _, err := c.materialize(t); err != nil {
+			return err
+		}
+	}
+	// Registrations of ancestors were materialized when they were built
+	pending := slices.DeleteFunc(c.uniqueSites(), func(site callSiteInterface) bool { return !c.owns(site) })
+	for len(pending) > 0 {
+		site := pending[0]
+		pending = pending[1:]
+		for i, t := range site.DepTypes() {
+			if _, ok := c.lookup(site.Deps()[i]); ok {
+				continue
+			}
+			materialized, err := c.materialize(t)
+			if err != nil {
+				return fmt.Errorf("%w: requested by %s", err, site.Name())
+			}
+			if materialized != nil {
+				pending = append(pending, materialized)
+			}
+		}
+	}
+	return nil
+}
+
+// materialize registers the instantiation t, or *t, of an open generic type.
+// It returns nil when t is registered or not an instantiation of an open generic type.
+func (c *Container) materialize(t reflect.Type) (callSiteInterface, error) {
+	family, ok := genericFamily(t)
+	if !ok {
+		return nil, nil
+	}
+	template, ok := c.openGeneric(family)
+	if !ok {
+		return nil, nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := t.String()
+	if _, ok := c.lookup(name); ok {
+		return nil, nil
+	}
+
+	initFunc, err := initMethodOf(t, name)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := []string{}
+	for i := 1; i < initFunc.Type.NumIn(); i++ {
+		depName := initFunc.Type.In(i).String()
+		if slices.Contains(dependencies, depName) {
+			return nil, fmt.Errorf("Dependency %s already exists for %s", depName, name)
+		}
+		dependencies = append(dependencies, depName)
+	}
+
+	// The instantiation has no static type: T is unused and instances are allocated from typ
+	site := &callSite[any]{
+		name:            name,
+		lifetime:        template.lifetime,
+		dependencyNames: dependencies,
+		initMethod:      initFunc,
+		options:         newServiceOptions(template.opts),
+		validate:        needsValidation(t),
+		typ:             t,
+	}
+	c.callSitesRegistry[name] = site
+	c.callSitesRegistry["*"+name] = site
+	c.log(slog.LevelDebug, "open generic materialized", append(serviceAttrs(name, template.lifetime),
+		slog.String("family", family), slog.Any("dependencies", dependencies))...)
+	return site, nil
+}
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type User struct{ Name string }
+type Order struct{ ID int }
+
+type Repository[T any] struct {
+	Counter *Counter
+	Items   []T
+}
+
+func (r *Repository[T]) Init(counter *Counter) error {
+	counter.I++
+	r.Counter = counter
+	return nil
+}
+
+type UserService struct {
+	Users  *Repository[User]
+	Orders *Repository[Order]
+}
+
+func (s *UserService) Init(users *Repository[User], orders *Repository[Order]) error {
+	s.Users, s.Orders = users, orders
+	return nil
+}
+
+type Cache[K comparable, V any] struct{ entries map[K]V }
+
+func (c *Cache[K, V]) Init(users *Repository[User]) error {
+	c.entries = make(map[K]V)
+	return nil
+}
+
+type OrderReport struct{ Cache *Cache[int, Order] }
+
+func (r *OrderReport) Init(cache *Cache[int, Order]) error {
+	r.Cache = cache
+	return nil
+}
+
+func TestOpenGenericMaterializesRequestedInstantiations(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[Counter](c)
+	AddOpenGenericSingleton[Repository[any]](c)
+	AddTransientWithoutInterface[UserService](c)
+	c.Build()
+
+	first, err := RequireServicePtr[UserService](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve UserService: %v", err)
+	}
+	second, err := RequireServicePtr[UserService](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve UserService: %v", err)
+	}
+	if first.Users == nil || first.Orders == nil || first.Users.Counter == nil {
+		t.Fatalf("Expected repositories to be injected, got %+v", first)
+	}
+	if first.Users != second.Users || first.Orders != second.Orders {
+		t.Error("Expected singleton repositories")
+	}
+	if first.Users.Counter.I != 2 {
+		t.Errorf("Expected one construction per instantiation, got %d", first.Users.Counter.I)
+	}
+
+	users, err := RequireServicePtr[Repository[User]](c)
+	if err != nil || users != first.Users {
+		t.Errorf("Expected the materialized Repository[User], got %v, %v", users, err)
+	}
+	if _, err := RequireServicePtr[Repository[int]](c); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("Expected unrequested instantiation not to be materialized, got %v", err)
+	}
+}
+
+func TestOpenGenericTransitiveAndEntryPoints(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[Counter](c)
+	AddOpenGenericTransient[Repository[any]](c)
+	AddOpenGenericScoped[Cache[any, any]](c)
+	AddScopedWithoutInterface[OrderReport](c)
+	AddEntryPoint[*Repository[Order]](c)
+	c.Build()
+
+	scope := c.CreateScope()
+	report, err := RequireServicePtrForScope[OrderReport](scope)
+	if err != nil {
+		t.Fatalf("Failed to resolve OrderReport: %v", err)
+	}
+	cache, err := RequireServicePtrForScope[Cache[int, Order]](scope)
+	if err != nil || cache != report.Cache {
+		t.Errorf("Expected scoped cache, got %v, %v", cache, err)
+	}
+	if _, err := RequireServicePtr[Repository[Order]](c); err != nil {
+		t.Errorf("Expected entry point to be materialized, got %v", err)
+	}
+	for _, d := range c.Describe() {
+		if strings.HasPrefix(d.Type, "container.Repository[") && d.Lifetime != Transient {
+			t.Errorf("Expected transient repositories, got %+v", d)
+		}
+	}
+}
+
+func TestOpenGenericValidatedAtBuild(t *testing.T) {
+	c := &Container{}
+	AddOpenGenericSingleton[Repository[any]](c)
+	AddTransientWithoutInterface[UserService](c)
+
+	err := buildPanic(c)
+	if !errors.Is(err, ErrDependencyNotFound) || !strings.Contains(err.Error(), "*container.Counter") {
+		t.Errorf("Expected missing dependency of the instantiation, got %v", err)
+	}
+}
+
+func TestOpenGenericRegistrationErrors(t *testing.T) {
+	for name, register := range map[string]func(c *Container){
+		"not generic": func(c *Container) { AddOpenGenericSingleton[Counter](c) },
+		"not struct":  func(c *Container) { AddOpenGenericSingleton[*Repository[any]](c) },
+		"twice": func(c *Container) {
+			AddOpenGenericSingleton[Repository[any]](c)
+			AddOpenGenericTransient[Repository[int]](c)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrNotGenericType) && !errors.Is(err, ErrShouldBeStructType) && !errors.Is(err, ErrTypeAlreadyRegistered) {
+					t.Errorf("Unexpected panic %v", err)
+				}
+			}()
+			register(&Container{})
+		})
+	}
+}
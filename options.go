@@ -0,0 +1,27 @@
+package container
+
+import "time"
+
+// Option configures a single service registration.
+//
+// Options are passed to registration functions such as AddHostedService:
+//
+//	AddHostedService[Worker](c, WithRestartPolicy(RestartPolicy{Mode: RestartOnFailure}))
+type Option func(*serviceOptions)
+
+type serviceOptions struct {
+	restartPolicy      *RestartPolicy
+	tags               []string
+	healthCheckTimeout time.Duration
+	startTimeout       time.Duration
+	stopTimeout        time.Duration
+	constructionRetry  ConstructionRetry
+}
+
+func newServiceOptions(opts []Option) serviceOptions {
+	var options serviceOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
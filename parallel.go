@@ -0,0 +1,49 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// runParallel calls fn for every index in items, running at most limit calls at once.
+// A limit of zero or less means no limit. Once ctx is done, remaining items are not
+// started and get the error of ctx. It waits for all calls to finish and returns their
+// errors joined in the order of items, so the result does not depend on scheduling.
+func runParallel(ctx context.Context, items []int, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for pos, i := range items {
+		if !acquire(ctx, sem) {
+			errs[pos] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[pos] = fn(ctx, i)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// acquire takes a slot of sem, and reports false without taking one when ctx is done first.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,64 @@
+package container
+
+import "context"
+
+// contextDependencyName is the dependency name of Init parameters of type context.Context.
+var contextDependencyName = nameForT[context.Context]()
+
+type resolutionContextKey struct{}
+
+// withResolutionContext marks ctx as the context of a resolution started with one of
+// the *Ctx functions, so it is injected into Init methods accepting context.Context.
+func withResolutionContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resolutionContextKey{}, true)
+}
+
+func hasResolutionContext(ctx context.Context) bool {
+	return ctx.Value(resolutionContextKey{}) != nil
+}
+
+// resolutionContext returns the context injected into Init parameters of type
+// context.Context that are not registered in the container.
+func resolutionContext(ctx context.Context) context.Context {
+	if hasResolutionContext(ctx) {
+		return ctx
+	}
+	return context.Background()
+}
+
+// RequireServiceCtx resolves a service instance from the container's global scope
+// within the given context.
+//
+// The context is passed to Init methods accepting a context.Context parameter, taking
+// precedence over a context.Context registered with AddValue. Construction of
+// dependencies stops as soon as the context is done, and the returned error wraps
+// ctx.Err() (e.g. context.DeadlineExceeded) together with the resolution path.
+// A singleton whose construction was aborted this way is constructed again on the
+// next resolution.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	db, err := RequireServiceCtx[IDatabase](ctx, container)
+func RequireServiceCtx[T any](ctx context.Context, c *Container) (T, error) {
+	return RequireServiceForScopeCtx[T](ctx, c.global)
+}
+
+// RequireServicePtrCtx resolves a service instance from the container's global scope
+// within the given context. See RequireServiceCtx for the context semantics.
+func RequireServicePtrCtx[T any](ctx context.Context, c *Container) (*T, error) {
+	return RequireServicePtrForScopeCtx[T](ctx, c.global)
+}
+
+// RequireServiceForScopeCtx resolves a service instance from the specified scope
+// within the given context. See RequireServiceCtx for the context semantics.
+func RequireServiceForScopeCtx[T any](ctx context.Context, s *Scope) (T, error) {
+	return requireService[T](withResolutionContext(ctx), s)
+}
+
+// RequireServicePtrForScopeCtx resolves a service instance from the specified scope
+// within the given context. See RequireServiceCtx for the context semantics.
+func RequireServicePtrForScopeCtx[T any](ctx context.Context, s *Scope) (*T, error) {
+	return requireServicePtr[T](withResolutionContext(ctx), s)
+}
@@ -0,0 +1,135 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type SlowDatabase struct{}
+
+func (s *SlowDatabase) Init(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+}
+
+type DatabaseConsumer struct{ db *SlowDatabase }
+
+func (d *DatabaseConsumer) Init(db *SlowDatabase) error {
+	d.db = db
+	return nil
+}
+
+func TestRequireServiceCtxInjectsContext(t *testing.T) {
+	c := &Container{}
+	AddTransientWithoutInterface[WithContextDependency](c)
+	c.Build()
+
+	dep, err := RequireServicePtr[WithContextDependency](c)
+	if err != nil {
+		t.Fatalf("Failed to resolve without registered context: %v", err)
+	}
+	if dep.Ctx == nil {
+		t.Errorf("Expected background context to be injected")
+	}
+
+	ctx := context.WithValue(context.Background(), TestKey, "resolution")
+	dep, err = RequireServicePtrCtx[WithContextDependency](ctx, c)
+	if err != nil {
+		t.Fatalf("Failed to resolve with context: %v", err)
+	}
+	if dep.Ctx.Value(TestKey) != "resolution" {
+		t.Errorf("Expected resolution context to be injected, got %v", dep.Ctx.Value(TestKey))
+	}
+}
+
+func TestRequireServiceCtxOverridesRegisteredContext(t *testing.T) {
+	c := &Container{}
+	AddTransientWithoutInterface[WithContextDependency](c)
+	AddValue(c, context.WithValue(context.Background(), TestKey, "registered"))
+	c.Build()
+
+	dep, _ := RequireServicePtr[WithContextDependency](c)
+	if dep.Ctx.Value(TestKey) != "registered" {
+		t.Errorf("Expected registered context, got %v", dep.Ctx.Value(TestKey))
+	}
+
+	ctx := context.WithValue(context.Background(), TestKey, "resolution")
+	dep, _ = RequireServicePtrCtx[WithContextDependency](ctx, c)
+	if dep.Ctx.Value(TestKey) != "resolution" {
+		t.Errorf("Expected resolution context, got %v", dep.Ctx.Value(TestKey))
+	}
+}
+
+func TestRequireServiceCtxDeadline(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[SlowDatabase](c)
+	AddTransientWithoutInterface[DatabaseConsumer](c)
+	c.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := RequireServicePtrCtx[DatabaseConsumer](ctx, c)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "container.SlowDatabase") {
+		t.Errorf("Expected resolution path in error, got %v", err)
+	}
+
+	_, err = RequireServicePtrCtx[DatabaseConsumer](context.Background(), c)
+	if err != nil {
+		t.Errorf("Cancelled singleton construction should not be cached, got %v", err)
+	}
+}
+
+func TestRequireServiceCtxDeadlineWhileSingletonIsConstructed(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[SlowDatabase](c)
+	AddTransientWithoutInterface[DatabaseConsumer](c)
+	c.Build()
+
+	constructed := make(chan error, 1)
+	go func() {
+		_, err := RequireServicePtr[SlowDatabase](c)
+		constructed <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	_, err := RequireServicePtrCtx[DatabaseConsumer](ctx, c)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(begin) >= 40*time.Millisecond {
+		t.Errorf("Expected the wait for the singleton to end with the context, waited %s", time.Since(begin))
+	}
+	if !strings.Contains(err.Error(), "container.SlowDatabase") {
+		t.Errorf("Expected resolution path in error, got %v", err)
+	}
+	if err := <-constructed; err != nil {
+		t.Errorf("Expected the construction in progress to succeed, got %v", err)
+	}
+}
+
+func TestRequireServiceCtxCancelled(t *testing.T) {
+	c := &Container{}
+	AddTransientWithoutInterface[DatabaseConsumer](c)
+	AddTransientWithoutInterface[SlowDatabase](c)
+	c.Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RequireServicePtrCtx[DatabaseConsumer](ctx, c)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
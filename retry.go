@@ -0,0 +1,79 @@
+package container
+
+import "time"
+
+// RetryMode defines what happens when the construction of a singleton fails.
+type RetryMode int
+
+const (
+	// CacheFailure keeps the first construction error forever. Every later resolution
+	// returns the same error. This is the default.
+	CacheFailure RetryMode = iota
+	// RetryOnResolve constructs the singleton again on every resolution until it succeeds.
+	RetryOnResolve
+	// RetryWithBackoff returns the cached error until the backoff delay has elapsed and
+	// then constructs the singleton again on the next resolution.
+	RetryWithBackoff
+)
+
+// ConstructionRetry configures how a singleton or hosted service is constructed again
+// after its Init method failed.
+//
+// A successfully constructed instance is always created exactly once, whatever the mode.
+// With RetryWithBackoff, the delay starts at InitialBackoff and doubles after every
+// failure up to MaxBackoff.
+type ConstructionRetry struct {
+	Mode           RetryMode
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// WithConstructionRetry sets how a singleton is constructed again after a failure.
+//
+// Example:
+//
+//	AddSingleton[IBroker, Broker](c, WithConstructionRetry(ConstructionRetry{
+//		Mode:           RetryWithBackoff,
+//		InitialBackoff: time.Second,
+//	}))
+func WithConstructionRetry(retry ConstructionRetry) Option {
+	return func(o *serviceOptions) { o.constructionRetry = retry }
+}
+
+func (r ConstructionRetry) shouldRetry(retryAt time.Time) bool {
+	switch r.Mode {
+	case RetryOnResolve:
+		return true
+	case RetryWithBackoff:
+		return !time.Now().Before(retryAt)
+	default:
+		return false
+	}
+}
+
+func (r ConstructionRetry) backoff(failures int) time.Duration {
+	if r.Mode != RetryWithBackoff {
+		return 0
+	}
+	return exponentialBackoff(r.InitialBackoff, r.MaxBackoff, failures)
+}
+
+// exponentialBackoff returns initial doubled attempt times, capped at maxDelay.
+// Zero or negative durations are replaced by DefaultRestartInitialBackoff and
+// DefaultRestartMaxBackoff.
+func exponentialBackoff(initial, maxDelay time.Duration, attempt int) time.Duration {
+	if initial <= 0 {
+		initial = DefaultRestartInitialBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRestartMaxBackoff
+	}
+	delay := initial
+	for range attempt {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return min(delay, maxDelay)
+}
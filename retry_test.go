@@ -0,0 +1,92 @@
+package container
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var flakyAttempts atomic.Int32
+
+type FlakyBroker struct{}
+
+func (f *FlakyBroker) Init() error {
+	if flakyAttempts.Add(1) == 1 {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestConstructionCacheFailure(t *testing.T) {
+	flakyAttempts.Store(0)
+	c := &Container{}
+	AddSingletonWithoutInterface[FlakyBroker](c)
+	c.Build()
+
+	for range 2 {
+		if _, err := RequireServicePtr[FlakyBroker](c); !errors.Is(err, ErrFailedToBuildDependency) {
+			t.Errorf("Expected cached ErrFailedToBuildDependency, got %v", err)
+		}
+	}
+	if flakyAttempts.Load() != 1 {
+		t.Errorf("Expected 1 construction attempt, got %d", flakyAttempts.Load())
+	}
+}
+
+func TestConstructionRetryOnResolve(t *testing.T) {
+	flakyAttempts.Store(0)
+	c := &Container{}
+	AddSingletonWithoutInterface[FlakyBroker](c, WithConstructionRetry(ConstructionRetry{Mode: RetryOnResolve}))
+	c.Build()
+
+	if _, err := RequireServicePtr[FlakyBroker](c); err == nil {
+		t.Fatalf("Expected first construction to fail")
+	}
+
+	var wg sync.WaitGroup
+	instances := make([]*FlakyBroker, 8)
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instances[i], _ = RequireServicePtr[FlakyBroker](c)
+		}()
+	}
+	wg.Wait()
+
+	for _, instance := range instances {
+		if instance == nil || instance != instances[0] {
+			t.Fatalf("Expected the same instance from every resolution, got %v", instances)
+		}
+	}
+	if flakyAttempts.Load() != 2 {
+		t.Errorf("Expected exactly one successful construction after the failure, got %d attempts", flakyAttempts.Load())
+	}
+}
+
+func TestConstructionRetryWithBackoff(t *testing.T) {
+	flakyAttempts.Store(0)
+	c := &Container{}
+	AddSingletonWithoutInterface[FlakyBroker](c, WithConstructionRetry(ConstructionRetry{
+		Mode:           RetryWithBackoff,
+		InitialBackoff: 20 * time.Millisecond,
+	}))
+	c.Build()
+
+	if _, err := RequireServicePtr[FlakyBroker](c); err == nil {
+		t.Fatalf("Expected first construction to fail")
+	}
+	if _, err := RequireServicePtr[FlakyBroker](c); err == nil {
+		t.Errorf("Expected cached error during backoff")
+	}
+	if flakyAttempts.Load() != 1 {
+		t.Errorf("Expected no retry during backoff, got %d attempts", flakyAttempts.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := RequireServicePtr[FlakyBroker](c); err != nil {
+		t.Errorf("Expected retry after backoff to succeed, got %v", err)
+	}
+}
@@ -1,7 +1,11 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"reflect"
 )
 
@@ -14,11 +18,45 @@ import (
 //
 // Scopes are created using Container.CreateScope() and should be used with
 // RequireServiceFor and RequireServiceForI functions for service resolution.
+// Close the scope when it is no longer used to dispose its instances.
 type Scope struct {
 	*Container
 
+	id        uint64
+	tenant    string
+	hasTenant bool
 	isGlobal  bool
+	closed    bool
 	instances map[string]any
+	created   []string // names of instances in creation order
+}
+
+// ID returns the identifier of the scope, unique within its container.
+// The global scope has the identifier zero.
+func (s *Scope) ID() uint64 { return s.id }
+
+// Close disposes the scope. Scoped instances implementing io.Closer are closed in
+// reverse creation order and all their errors are returned joined. Resolving scoped
+// services from a closed scope fails with ErrScopeClosed. Closing a scope twice is a no-op.
+func (s *Scope) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for i := len(s.created) - 1; i >= 0; i-- {
+		name := s.created[i]
+		if closer, ok := s.instances[name].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close %s: %w", name, err))
+			}
+		}
+	}
+	s.instances = nil
+	s.created = nil
+	s.log(slog.LevelDebug, "scope closed", slog.Uint64("scope", s.id), slog.Int("errors", len(errs)))
+	return errors.Join(errs...)
 }
 
 func unwrapT[T any](v any) (T, error) {
@@ -97,6 +135,7 @@ func unwrapPtrT[T any](v any) (*T, error) {
 //   - Transient: Creates a new instance for each call
 //   - Scoped: Returns the same instance within the same scope, creates new for different scopes
 //   - HostedService: Behaves like singleton
+//   - TenantSingleton: Returns the same instance for all scopes of the tenant, see CreateTenantScope
 //
 // The function panics if:
 //   - The container is not built
@@ -113,6 +152,18 @@ func unwrapPtrT[T any](v any) (*T, error) {
 //		return
 //	}
 func RequireServicePtrForScope[T any](s *Scope) (*T, error) {
+	return requireServicePtr[T](context.Background(), s)
+}
+
+// RequireServiceForScope resolves a service instance of type T from the specified scope.
+//
+// It behaves like RequireServicePtrForScope but returns the service itself and should be
+// used for pointer to struct types or interface types.
+func RequireServiceForScope[T any](s *Scope) (T, error) {
+	return requireService[T](context.Background(), s)
+}
+
+func requireServicePtr[T any](ctx context.Context, s *Scope) (*T, error) {
 	if !s.built {
 		panic(fmt.Errorf("%w: You should call Build() before RequireService", ErrContainerNotBuilt))
 	}
@@ -126,18 +177,18 @@ func RequireServicePtrForScope[T any](s *Scope) (*T, error) {
 	// 		panic(fmt.Errorf("%w Maybe you should use RequireService[T] for interfaces?", ErrExtractDependencyName))
 	// 	}
 	// }
-	item, ok := s.callSitesRegistry[nameDep]
+	item, ok := s.lookup(nameDep)
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrDependencyNotFound, nameDep)
 	}
-	dep, err := item.Build(s)
+	dep, err := item.Build(ctx, s)
 	if err != nil {
-		return nil, err
+		return nil, withRequested(err, reflect.TypeFor[T]().String())
 	}
 	return unwrapPtrT[T](dep)
 }
 
-func RequireServiceForScope[T any](s *Scope) (T, error) {
+func requireService[T any](ctx context.Context, s *Scope) (T, error) {
 	if !s.built {
 		panic(fmt.Errorf("%w: You should call Build() before RequireServiceFor", ErrContainerNotBuilt))
 	}
@@ -154,13 +205,13 @@ func RequireServiceForScope[T any](s *Scope) (T, error) {
 			panic(fmt.Errorf("%w", ErrExtractDependencyName))
 		}
 	}
-	item, ok := s.callSitesRegistry[nameDep]
+	item, ok := s.lookup(nameDep)
 	if !ok {
 		return *new(T), fmt.Errorf("%w: %s", ErrDependencyNotFound, nameDep)
 	}
-	dep, err := item.Build(s)
+	dep, err := item.Build(ctx, s)
 	if err != nil {
-		return *new(T), err
+		return *new(T), withRequested(err, reflect.TypeFor[T]().String())
 	}
 	return unwrapT[T](dep)
 }
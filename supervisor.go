@@ -0,0 +1,149 @@
+package container
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// IBackgroundService is a hosted service whose background work keeps running after Start.
+//
+// The container calls Done after every successful Start. The returned channel receives
+// the result of the background work when it ends: nil when the work completed and an
+// error when it crashed. Hosted services registered with a RestartPolicy are restarted
+// according to that policy.
+type IBackgroundService interface {
+	IHostedService
+	Done() <-chan error
+}
+
+// RestartMode defines when a supervised hosted service is restarted.
+type RestartMode int
+
+const (
+	// RestartNever never restarts the service.
+	RestartNever RestartMode = iota
+	// RestartOnFailure restarts the service when its background work ends with an error.
+	RestartOnFailure
+	// RestartAlways restarts the service whenever its background work ends.
+	RestartAlways
+)
+
+// Default backoff values of RestartPolicy and ConstructionRetry.
+const (
+	DefaultRestartInitialBackoff = 100 * time.Millisecond
+	DefaultRestartMaxBackoff     = 30 * time.Second
+)
+
+// RestartPolicy configures how the container supervises a hosted service that
+// implements IBackgroundService.
+//
+// Restarts are delayed with an exponential backoff starting at InitialBackoff and
+// doubling up to MaxBackoff with every consecutive failure. A service that stays up for
+// MaxBackoff after a (re)start is restarted again after InitialBackoff. When MaxRestarts is positive, at most MaxRestarts restarts
+// are allowed within Window (or during the whole process lifetime when Window is zero).
+// Once the policy is exhausted the service is no longer restarted and, when
+// EscalateOnExhausted is set, the whole application is stopped via
+// IHostApplicationLifetime.StopApplication.
+type RestartPolicy struct {
+	Mode                RestartMode
+	InitialBackoff      time.Duration
+	MaxBackoff          time.Duration
+	MaxRestarts         int
+	Window              time.Duration
+	EscalateOnExhausted bool
+}
+
+// WithRestartPolicy sets the restart policy of a hosted service.
+func WithRestartPolicy(policy RestartPolicy) Option {
+	return func(o *serviceOptions) { o.restartPolicy = &policy }
+}
+
+func (p RestartPolicy) shouldRestart(err error) bool {
+	switch p.Mode {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (p RestartPolicy) backoff(attempt int) time.Duration {
+	return exponentialBackoff(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// supervise watches the background work of the started hosted service i and restarts
+// it according to its restart policy until the application is stopping.
+func (c *Container) supervise(i int) {
+	entry := c.hostedServices[i]
+	policy := entry.site.Options().restartPolicy
+	background, ok := entry.service.(IBackgroundService)
+	if policy == nil || !ok {
+		return
+	}
+	stopping := c.appLifetime().ApplicationStopping()
+
+	c.supervisors.Add(1)
+	go func() {
+		defer c.supervisors.Done()
+		var restarts []time.Time
+		attempt := 0
+		stableAfter := policy.backoff(math.MaxInt) // MaxBackoff, or its default
+		upSince := time.Now()
+		done := background.Done()
+		for {
+			var err error
+			select {
+			case <-stopping:
+				return
+			case err = <-done:
+			}
+			if time.Since(upSince) >= stableAfter {
+				attempt = 0
+			}
+
+			for {
+				if !policy.shouldRestart(err) {
+					if err != nil && policy.EscalateOnExhausted {
+						c.appLifetime().StopApplication()
+					}
+					return
+				}
+				now := time.Now()
+				if policy.Window > 0 {
+					kept := restarts[:0]
+					for _, at := range restarts {
+						if now.Sub(at) < policy.Window {
+							kept = append(kept, at)
+						}
+					}
+					restarts = kept
+				}
+				if policy.MaxRestarts > 0 && len(restarts) >= policy.MaxRestarts {
+					if policy.EscalateOnExhausted {
+						c.appLifetime().StopApplication()
+					}
+					return
+				}
+
+				select {
+				case <-stopping:
+					return
+				case <-time.After(policy.backoff(attempt)):
+				}
+				attempt++
+				restarts = append(restarts, time.Now())
+
+				ctx := context.Background()
+				_ = c.stopHostedService(ctx, i)
+				if err = c.startHostedService(ctx, i); err == nil {
+					upSince = time.Now()
+					break
+				}
+			}
+			done = background.Done()
+		}
+	}()
+}
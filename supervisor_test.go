@@ -0,0 +1,150 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type CrashingWorker struct {
+	mu     sync.Mutex
+	starts int
+	done   chan error
+}
+
+func (w *CrashingWorker) Init() error { return nil }
+func (w *CrashingWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.starts++
+	w.done = make(chan error, 1)
+	return nil
+}
+func (w *CrashingWorker) Stop(ctx context.Context) error { return nil }
+func (w *CrashingWorker) Done() <-chan error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.done
+}
+func (w *CrashingWorker) crash(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done <- err
+}
+func (w *CrashingWorker) startCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.starts
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRestartOnFailure(t *testing.T) {
+	c := &Container{}
+	AddHostedService[CrashingWorker](c, WithRestartPolicy(RestartPolicy{
+		Mode:           RestartOnFailure,
+		InitialBackoff: time.Millisecond,
+	}))
+	c.Build()
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	worker, _ := RequireServicePtr[CrashingWorker](c)
+
+	worker.crash(errors.New("connection lost"))
+	waitFor(t, func() bool { return worker.startCount() == 2 })
+
+	worker.crash(nil)
+	time.Sleep(10 * time.Millisecond)
+	if worker.startCount() != 2 {
+		t.Errorf("Completed worker should not be restarted, got %d starts", worker.startCount())
+	}
+
+	if err := c.StopAsync(ctx); err != nil {
+		t.Errorf("StopAsync failed: %v", err)
+	}
+}
+
+func TestRestartPolicyExhaustedEscalates(t *testing.T) {
+	c := &Container{}
+	AddHostedService[CrashingWorker](c, WithRestartPolicy(RestartPolicy{
+		Mode:                RestartAlways,
+		InitialBackoff:      time.Millisecond,
+		MaxRestarts:         1,
+		Window:              time.Minute,
+		EscalateOnExhausted: true,
+	}))
+	c.Build()
+
+	if err := c.StartAsync(context.Background()); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	worker, _ := RequireServicePtr[CrashingWorker](c)
+
+	worker.crash(errors.New("first crash"))
+	waitFor(t, func() bool { return worker.startCount() == 2 })
+	worker.crash(errors.New("second crash"))
+
+	select {
+	case <-c.Lifetime().ApplicationStopped():
+	case <-time.After(time.Second):
+		t.Fatalf("Application was not stopped after restart policy was exhausted")
+	}
+	if worker.startCount() != 2 {
+		t.Errorf("Expected 2 starts, got %d", worker.startCount())
+	}
+}
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	policy := RestartPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, want := range expected {
+		if got := policy.backoff(attempt); got != want {
+			t.Errorf("Backoff for attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestRestartBackoffResetsAfterStableRun(t *testing.T) {
+	c := &Container{}
+	AddHostedService[CrashingWorker](c, WithRestartPolicy(RestartPolicy{
+		Mode:           RestartOnFailure,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     200 * time.Millisecond,
+	}))
+	c.Build()
+
+	ctx := context.Background()
+	if err := c.StartAsync(ctx); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	defer c.StopAsync(ctx)
+	worker, _ := RequireServicePtr[CrashingWorker](c)
+
+	// Consecutive failures back off for 20, 40, 80 and 160ms
+	for starts := 2; starts <= 5; starts++ {
+		worker.crash(errors.New("connection lost"))
+		waitFor(t, func() bool { return worker.startCount() == starts })
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	begin := time.Now()
+	worker.crash(errors.New("connection lost"))
+	waitFor(t, func() bool { return worker.startCount() == 6 })
+	if elapsed := time.Since(begin); elapsed >= 150*time.Millisecond {
+		t.Errorf("Expected the backoff to restart at InitialBackoff after a stable run, waited %s", elapsed)
+	}
+}
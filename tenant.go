@@ -0,0 +1,130 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// tenantInstances holds the TenantSingleton instances of one tenant.
+type tenantInstances struct {
+	mu      sync.Mutex
+	evicted bool
+	entries map[string]*tenantEntry
+	created []string // names of constructed instances in creation order
+}
+
+// errTenantEvicted is returned internally when the instances of a tenant were evicted
+// while resolving from them. The resolution is then retried with new instances.
+var errTenantEvicted = errors.New("tenant evicted")
+
+// tenantEntry is the instance of one TenantSingleton call site for one tenant.
+type tenantEntry struct {
+	mu          sync.Mutex
+	constructed bool
+	instance    any
+}
+
+// AddTenantSingletonWithoutInterface registers a tenant singleton service without an interface mapping.
+//
+// Tenant singletons are created once per tenant and reused by all scopes of that tenant,
+// created with CreateTenantScope, until the tenant is evicted with EvictTenant.
+func AddTenantSingletonWithoutInterface[T any](c *Container, opts ...Option) {
+	add[T](c, TenantSingleton, opts...)
+}
+
+// AddTenantSingleton registers a tenant singleton service with interface mapping.
+//
+// Tenant singletons are created once per tenant and reused by all scopes of that tenant,
+// created with CreateTenantScope, until the tenant is evicted with EvictTenant.
+//
+// Type parameters:
+//   - I: The interface type that will be used for service resolution
+//   - T: The concrete implementation type that implements interface I
+func AddTenantSingleton[I any, T any](c *Container, opts ...Option) {
+	addI[I, T](c, TenantSingleton, opts...)
+}
+
+// CreateTenantScope creates a scope for the given tenant.
+//
+// A tenant scope behaves like a scope created with CreateScope, and additionally resolves
+// TenantSingleton services from the instances of its tenant.
+//
+// Example:
+//
+//	scope := c.CreateTenantScope(r.Header.Get("X-Tenant"))
+//	defer scope.Close()
+//	billing, err := RequireServiceForScope[IBilling](scope)
+func (c *Container) CreateTenantScope(tenant string) *Scope {
+	scope := c.CreateScope()
+	scope.tenant = tenant
+	scope.hasTenant = true
+	return scope
+}
+
+// Tenant returns the tenant of a scope created with CreateTenantScope.
+func (s *Scope) Tenant() (string, bool) { return s.tenant, s.hasTenant }
+
+// EvictTenant disposes the TenantSingleton instances of the tenant. Instances implementing
+// io.Closer are closed in reverse creation order and all their errors are returned joined.
+//
+// Scopes of the tenant resolving TenantSingleton services after the eviction get new
+// instances. Instances whose construction was in progress during the eviction are closed
+// and constructed again. Evicting a tenant without instances is a no-op.
+func (c *Container) EvictTenant(tenant string) error {
+	c.tenantsMu.Lock()
+	instances := c.tenants[tenant]
+	delete(c.tenants, tenant)
+	c.tenantsMu.Unlock()
+	if instances == nil {
+		return nil
+	}
+
+	instances.mu.Lock()
+	instances.evicted = true
+	created := make([]*tenantEntry, len(instances.created))
+	for i, name := range instances.created {
+		created[i] = instances.entries[name]
+	}
+	names := instances.created
+	instances.mu.Unlock()
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		created[i].mu.Lock()
+		instance := created[i].instance
+		created[i].mu.Unlock()
+		if closer, ok := instance.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close %s: %w", names[i], err))
+			}
+		}
+	}
+	c.log(slog.LevelDebug, "tenant evicted", slog.String("tenant", tenant), slog.Int("errors", len(errs)))
+	return errors.Join(errs...)
+}
+
+// tenantEntry returns the entry of the call site name for the tenant, creating it if needed.
+func (c *Container) tenantEntry(tenant, name string) (*tenantInstances, *tenantEntry) {
+	c.tenantsMu.Lock()
+	if c.tenants == nil {
+		c.tenants = make(map[string]*tenantInstances)
+	}
+	instances, ok := c.tenants[tenant]
+	if !ok {
+		instances = &tenantInstances{entries: make(map[string]*tenantEntry)}
+		c.tenants[tenant] = instances
+	}
+	c.tenantsMu.Unlock()
+
+	instances.mu.Lock()
+	defer instances.mu.Unlock()
+	entry, ok := instances.entries[name]
+	if !ok {
+		entry = &tenantEntry{}
+		instances.entries[name] = entry
+	}
+	return instances, entry
+}
@@ -0,0 +1,187 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type TenantSettings struct{ Counter *Counter }
+
+func (s *TenantSettings) Init(counter *Counter) error { s.Counter = counter; return nil }
+
+type TenantOverScoped struct{}
+
+func (t *TenantOverScoped) Init(c *C) error { return nil }
+
+type SingletonOverTenant struct{}
+
+func (s *SingletonOverTenant) Init(t *TenantSettings) error { return nil }
+
+func TestTenantSingleton(t *testing.T) {
+	c := GetContainer()
+	AddTenantSingletonWithoutInterface[TenantSettings](c)
+	c.Build()
+
+	acme := c.CreateTenantScope("acme")
+	first, err := RequireServicePtrForScope[TenantSettings](acme)
+	if err != nil {
+		t.Fatalf("Failed to resolve TenantSettings: %v", err)
+	}
+	second, err := RequireServicePtrForScope[TenantSettings](c.CreateTenantScope("acme"))
+	if err != nil || second != first {
+		t.Errorf("Expected the same instance for scopes of the same tenant, got %p, %v", second, err)
+	}
+	other, err := RequireServicePtrForScope[TenantSettings](c.CreateTenantScope("globex"))
+	if err != nil || other == first {
+		t.Errorf("Expected another instance for another tenant, got %p, %v", other, err)
+	}
+	if tenant, ok := acme.Tenant(); !ok || tenant != "acme" {
+		t.Errorf("Expected tenant acme, got %q, %v", tenant, ok)
+	}
+
+	if _, err := RequireServicePtrForScope[TenantSettings](c.CreateScope()); !errors.Is(err, ErrScopeWithoutTenant) {
+		t.Errorf("Expected ErrScopeWithoutTenant, got %v", err)
+	}
+	if _, err := RequireServicePtr[TenantSettings](c); !errors.Is(err, ErrScopeWithoutTenant) {
+		t.Errorf("Expected ErrScopeWithoutTenant from the global scope, got %v", err)
+	}
+}
+
+func TestTenantSingletonConcurrentResolution(t *testing.T) {
+	c := GetContainer()
+	AddTenantSingletonWithoutInterface[TenantSettings](c)
+	c.Build()
+
+	var wg sync.WaitGroup
+	instances := make([]*TenantSettings, 16)
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instances[i], _ = RequireServicePtrForScope[TenantSettings](c.CreateTenantScope("acme"))
+		}()
+	}
+	wg.Wait()
+	for _, instance := range instances {
+		if instance == nil || instance != instances[0] {
+			t.Fatalf("Expected a single instance for the tenant, got %v", instances)
+		}
+	}
+}
+
+func TestEvictTenant(t *testing.T) {
+	closeLog := &CloseLog{}
+	c := &Container{}
+	AddValue(c, closeLog)
+	AddTenantSingletonWithoutInterface[FirstClosable](c)
+	AddTenantSingletonWithoutInterface[SecondClosable](c)
+	c.Build()
+
+	acme, globex := c.CreateTenantScope("acme"), c.CreateTenantScope("globex")
+	first, err := RequireServicePtrForScope[FirstClosable](acme)
+	if err != nil {
+		t.Fatalf("Failed to resolve first: %v", err)
+	}
+	if _, err := RequireServicePtrForScope[SecondClosable](acme); err != nil {
+		t.Fatalf("Failed to resolve second: %v", err)
+	}
+	if _, err := RequireServicePtrForScope[FirstClosable](globex); err != nil {
+		t.Fatalf("Failed to resolve first for globex: %v", err)
+	}
+
+	if err := c.EvictTenant("acme"); !errors.Is(err, errClose) {
+		t.Errorf("Expected close error, got %v", err)
+	}
+	if strings.Join(closeLog.Closed, ",") != "second,first" {
+		t.Errorf("Expected only the instances of acme closed in reverse order, got %v", closeLog.Closed)
+	}
+	if err := c.EvictTenant("acme"); err != nil {
+		t.Errorf("Expected evicting an empty tenant to be a no-op, got %v", err)
+	}
+
+	recreated, err := RequireServicePtrForScope[FirstClosable](acme)
+	if err != nil || recreated == first {
+		t.Errorf("Expected a new instance after eviction, got %p, %v", recreated, err)
+	}
+}
+
+func TestTenantSingletonCaptiveDependencies(t *testing.T) {
+	c := GetContainer()
+	AddTenantSingletonWithoutInterface[TenantOverScoped](c)
+	if err := buildPanic(c); !strings.Contains(err.Error(), "TenantSingleton container.TenantOverScoped captures Scoped container.C") {
+		t.Errorf("Expected TenantSingleton to capture Scoped, got %v", err)
+	}
+
+	c = GetContainer()
+	AddTenantSingletonWithoutInterface[TenantSettings](c)
+	AddSingletonWithoutInterface[SingletonOverTenant](c)
+	if err := buildPanic(c); !strings.Contains(err.Error(), "Singleton container.SingletonOverTenant captures TenantSingleton container.TenantSettings") {
+		t.Errorf("Expected Singleton to capture TenantSingleton, got %v", err)
+	}
+}
+
+type TenantResourceTracker struct{ created, closed atomic.Int32 }
+
+type TenantResource struct {
+	tracker *TenantResourceTracker
+	closed  atomic.Bool
+}
+
+func (r *TenantResource) Init(tracker *TenantResourceTracker) error {
+	time.Sleep(100 * time.Microsecond)
+	r.tracker = tracker
+	tracker.created.Add(1)
+	return nil
+}
+
+func (r *TenantResource) Close() error {
+	if r.closed.Swap(true) {
+		return errors.New("closed twice")
+	}
+	r.tracker.closed.Add(1)
+	return nil
+}
+
+func TestEvictTenantDuringResolution(t *testing.T) {
+	tracker := &TenantResourceTracker{}
+	c := &Container{}
+	AddValue(c, tracker)
+	AddTenantSingletonWithoutInterface[TenantResource](c)
+	c.Build()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := RequireServicePtrForScope[TenantResource](c.CreateTenantScope("acme")); err != nil {
+					t.Errorf("Failed to resolve TenantResource: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			if err := c.EvictTenant("acme"); err != nil {
+				t.Errorf("Failed to evict: %v", err)
+			}
+			time.Sleep(50 * time.Microsecond)
+		}
+	}()
+	wg.Wait()
+
+	if err := c.EvictTenant("acme"); err != nil {
+		t.Errorf("Failed to evict: %v", err)
+	}
+	if created, closed := tracker.created.Load(), tracker.closed.Load(); created != closed {
+		t.Errorf("Expected every instance to be closed, created %d and closed %d", created, closed)
+	}
+}
@@ -0,0 +1,183 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SpanInfo describes the construction traced by a span.
+type SpanInfo struct {
+	Name     string
+	Lifetime lifetime
+	// ScopeID is the ID of the resolving scope, zero for singletons and the global scope.
+	ScopeID uint64
+}
+
+// Span is a traced construction, ended once the constructor returns.
+type Span interface {
+	End(err error)
+}
+
+// Tracer opens a span per constructor call of a service.
+//
+// Dependencies are constructed within the context returned by StartSpan, so their
+// spans are nested in the span of the service depending on them.
+// Implementations must be safe for concurrent use.
+type Tracer interface {
+	StartSpan(ctx context.Context, info SpanInfo) (context.Context, Span)
+}
+
+// NoopTracer is a Tracer that records nothing. It is used when no tracer is set.
+type NoopTracer struct{}
+
+func (NoopTracer) StartSpan(ctx context.Context, info SpanInfo) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(err error) {}
+
+// SetTracer sets the tracer of service constructions.
+//
+// Use NewTraceRecorder to record spans in memory.
+func (c *Container) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+func (c *Container) startSpan(ctx context.Context, name string, lifetime lifetime, s *Scope) (context.Context, Span) {
+	if c == nil || c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	info := SpanInfo{Name: name, Lifetime: lifetime}
+	if s != nil {
+		info.ScopeID = s.id
+	}
+	return c.tracer.StartSpan(ctx, info)
+}
+
+// RecordedSpan is a span recorded by TraceRecorder.
+type RecordedSpan struct {
+	ID uint64
+	// ParentID is the ID of the enclosing span, zero for root spans.
+	ParentID uint64
+	SpanInfo
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// TraceRecorder is a Tracer recording ended spans in memory.
+type TraceRecorder struct {
+	mu     sync.Mutex
+	nextID uint64
+	spans  []RecordedSpan
+}
+
+// NewTraceRecorder creates an empty TraceRecorder.
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{}
+}
+
+type recordedSpanKey struct{}
+
+type recorderSpan struct {
+	recorder *TraceRecorder
+	span     RecordedSpan
+}
+
+// StartSpan implements Tracer.
+func (r *TraceRecorder) StartSpan(ctx context.Context, info SpanInfo) (context.Context, Span) {
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.mu.Unlock()
+
+	span := &recorderSpan{recorder: r, span: RecordedSpan{ID: id, SpanInfo: info, Start: time.Now()}}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*recorderSpan); ok && parent.recorder == r {
+		span.span.ParentID = parent.span.ID
+	}
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+func (s *recorderSpan) End(err error) {
+	s.span.Duration = time.Since(s.span.Start)
+	s.span.Err = err
+	s.recorder.mu.Lock()
+	s.recorder.spans = append(s.recorder.spans, s.span)
+	s.recorder.mu.Unlock()
+}
+
+// Spans returns the ended spans in the order they ended: dependencies before the
+// services depending on them.
+func (r *TraceRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedSpan(nil), r.spans...)
+}
+
+// Reset removes all recorded spans.
+func (r *TraceRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+type chromeTraceEvent struct {
+	Name     string            `json:"name"`
+	Category string            `json:"cat"`
+	Phase    string            `json:"ph"`
+	Start    int64             `json:"ts"`
+	Duration int64             `json:"dur"`
+	PID      int               `json:"pid"`
+	TID      uint64            `json:"tid"`
+	Args     map[string]string `json:"args"`
+}
+
+// WriteChromeTrace writes the recorded spans in the Chrome trace-event JSON format,
+// which can be opened in chrome://tracing or https://ui.perfetto.dev.
+//
+// Each tree of nested spans is written on its own thread so that spans constructed
+// in parallel, e.g. by Warmup, do not overlap.
+func (r *TraceRecorder) WriteChromeTrace(w io.Writer) error {
+	spans := r.Spans()
+	parents := make(map[uint64]uint64, len(spans))
+	for _, span := range spans {
+		parents[span.ID] = span.ParentID
+	}
+	root := func(id uint64) uint64 {
+		for parents[id] != 0 {
+			id = parents[id]
+		}
+		return id
+	}
+
+	events := make([]chromeTraceEvent, 0, len(spans))
+	for _, span := range spans {
+		args := map[string]string{"lifetime": span.Lifetime.String()}
+		if span.ScopeID != 0 {
+			args["scope"] = strconv.FormatUint(span.ScopeID, 10)
+		}
+		if span.Err != nil {
+			args["error"] = span.Err.Error()
+		}
+		events = append(events, chromeTraceEvent{
+			Name:     span.Name,
+			Category: "construction",
+			Phase:    "X",
+			Start:    span.Start.UnixMicro(),
+			Duration: span.Duration.Microseconds(),
+			PID:      1,
+			TID:      root(span.ID),
+			Args:     args,
+		})
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string             `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
@@ -0,0 +1,95 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTraceRecorderNestsDependencySpans(t *testing.T) {
+	recorder := NewTraceRecorder()
+	c := &Container{}
+	c.SetTracer(recorder)
+	AddSingletonWithoutInterface[Counter](c)
+	AddTransientWithoutInterface[A](c)
+	AddScopedWithoutInterface[B](c)
+	c.Build()
+
+	scope := c.CreateScope()
+	if _, err := RequireServiceForScope[B](scope); err != nil {
+		t.Fatalf("Failed to resolve B: %v", err)
+	}
+
+	spans := recorder.Spans()
+	byName := make(map[string]RecordedSpan)
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+	if len(spans) != 3 || len(byName) != 3 {
+		t.Fatalf("Expected one span per constructor, got %+v", spans)
+	}
+	b, a, counter := byName["container.B"], byName["container.A"], byName["container.Counter"]
+	if b.ParentID != 0 || a.ParentID != b.ID || counter.ParentID != a.ID {
+		t.Errorf("Unexpected nesting: B=%+v A=%+v Counter=%+v", b, a, counter)
+	}
+	if b.Lifetime != Scoped || b.ScopeID != scope.ID() || counter.ScopeID != 0 {
+		t.Errorf("Unexpected annotations: B=%+v Counter=%+v", b, counter)
+	}
+	if b.Duration < a.Duration {
+		t.Errorf("Expected B span to enclose A span, got %s < %s", b.Duration, a.Duration)
+	}
+}
+
+func TestTraceRecorderRecordsErrors(t *testing.T) {
+	recorder := NewTraceRecorder()
+	c := &Container{}
+	c.SetTracer(recorder)
+	AddTransientWithoutInterface[ServiceWithFailingInit](c)
+	c.Build()
+
+	_, resolveErr := RequireService[ServiceWithFailingInit](c)
+	spans := recorder.Spans()
+	if len(spans) != 1 || !errors.Is(spans[0].Err, ErrFailedToBuildDependency) {
+		t.Fatalf("Expected failed span, got %+v (resolve error %v)", spans, resolveErr)
+	}
+}
+
+func TestTraceRecorderWritesChromeTrace(t *testing.T) {
+	recorder := NewTraceRecorder()
+	c := &Container{}
+	c.SetTracer(recorder)
+	AddSingletonWithoutInterface[Counter](c)
+	AddTransientWithoutInterface[A](c)
+	c.Build()
+	if _, err := RequireService[A](c); err != nil {
+		t.Fatalf("Failed to resolve A: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.WriteChromeTrace(&buf); err != nil {
+		t.Fatalf("Failed to write trace: %v", err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name  string            `json:"name"`
+			Phase string            `json:"ph"`
+			TID   uint64            `json:"tid"`
+			Args  map[string]string `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Invalid trace JSON: %v", err)
+	}
+	if len(trace.TraceEvents) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", trace.TraceEvents)
+	}
+	for _, event := range trace.TraceEvents {
+		if event.Phase != "X" || event.TID != trace.TraceEvents[1].TID {
+			t.Errorf("Expected complete events on the same thread, got %+v", trace.TraceEvents)
+		}
+	}
+	if trace.TraceEvents[0].Name != "container.Counter" || trace.TraceEvents[0].Args["lifetime"] != "Singleton" {
+		t.Errorf("Unexpected event %+v", trace.TraceEvents[0])
+	}
+}
@@ -0,0 +1,137 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// UnusedAlias is an interface a service is registered under while the service itself is unused.
+type UnusedAlias struct {
+	Interface      string
+	Implementation string
+}
+
+// UsageReport lists the registrations that are not reachable from any root.
+//
+// Roots are hosted services, health checks and entry points declared with AddEntryPoint.
+// Values and services registered automatically by Build are never reported.
+type UsageReport struct {
+	// Unreachable services, excluding values, ordered by name.
+	Unreachable []string
+	// UnusedAliases are the interfaces unreachable services are registered under.
+	UnusedAliases []UnusedAlias
+	// UnusedValues are the values registered with AddValue no root depends on.
+	UnusedValues []string
+}
+
+// Empty reports whether every registration is used.
+func (r UsageReport) Empty() bool {
+	return len(r.Unreachable) == 0 && len(r.UnusedAliases) == 0 && len(r.UnusedValues) == 0
+}
+
+func (r UsageReport) String() string {
+	var parts []string
+	if len(r.Unreachable) > 0 {
+		parts = append(parts, "unreachable services "+strings.Join(r.Unreachable, ", "))
+	}
+	if len(r.UnusedAliases) > 0 {
+		aliases := make([]string, len(r.UnusedAliases))
+		for i, alias := range r.UnusedAliases {
+			aliases[i] = alias.Interface + " -> " + alias.Implementation
+		}
+		parts = append(parts, "unused aliases "+strings.Join(aliases, ", "))
+	}
+	if len(r.UnusedValues) > 0 {
+		parts = append(parts, "unused values "+strings.Join(r.UnusedValues, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// AddEntryPoint declares T as resolved directly by the application, e.g. with RequireService.
+//
+// Entry points are roots of AnalyzeUsage in addition to hosted services and health checks,
+// and instantiations of open generic types declared as entry points are materialized by Build.
+// T is the type given to RequireService; it does not have to be registered yet.
+func AddEntryPoint[T any](c *Container) {
+	if c.built {
+		panic(fmt.Errorf("%w: Cannot add entry points after Build()", ErrContainerAlreadyBuilt))
+	}
+	c.entryPoints = append(c.entryPoints, reflect.TypeFor[T]())
+}
+
+// registryName returns the name services of type t are resolved with by RequireService.
+func registryName(t reflect.Type) string {
+	if t.Kind() == reflect.Interface {
+		return "*" + t.String()
+	}
+	return t.String()
+}
+
+// SetStrictUsage makes Build fail with ErrUnusedRegistration when AnalyzeUsage reports
+// unused registrations.
+func (c *Container) SetStrictUsage(strict bool) {
+	c.strictUsage = strict
+}
+
+// AnalyzeUsage reports the registrations that no root reaches through its dependencies.
+//
+// Dependencies are followed through the Init parameters of services, so services only
+// resolved directly by the application must be declared with AddEntryPoint.
+func (c *Container) AnalyzeUsage() UsageReport {
+	var roots []callSiteInterface
+	roots = append(roots, c.hostedServiceSites...)
+	for _, check := range c.healthChecks {
+		if check.site != nil {
+			roots = append(roots, check.site)
+		}
+	}
+	for _, t := range c.entryPoints {
+		if site, ok := c.lookup(registryName(t)); ok {
+			roots = append(roots, site)
+		}
+	}
+
+	reachable := make(map[callSiteInterface]bool)
+	var walk func(site callSiteInterface)
+	walk = func(site callSiteInterface) {
+		if reachable[site] {
+			return
+		}
+		reachable[site] = true
+		for _, depName := range site.Deps() {
+			if dep, ok := c.lookupFor(site, depName); ok {
+				walk(dep)
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+
+	builtIn := []string{nameForT[IHostApplicationLifetime](), nameForT[*HealthService]()}
+	var report UsageReport
+	for _, site := range c.uniqueSites() {
+		if reachable[site] || !c.owns(site) || slices.Contains(builtIn, site.Name()) {
+			continue
+		}
+		if site.Lifetime() == Value {
+			report.UnusedValues = append(report.UnusedValues, site.Name())
+		} else {
+			report.Unreachable = append(report.Unreachable, site.Name())
+		}
+	}
+	for key, site := range c.callSitesRegistry {
+		name := site.Name()
+		if reachable[site] || site.Lifetime() == Value || key == name || key == "*"+name {
+			continue
+		}
+		report.UnusedAliases = append(report.UnusedAliases, UnusedAlias{
+			Interface:      strings.TrimPrefix(key, "*"),
+			Implementation: name,
+		})
+	}
+	slices.SortFunc(report.UnusedAliases, func(a, b UnusedAlias) int { return strings.Compare(a.Interface, b.Interface) })
+	return report
+}
@@ -0,0 +1,77 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type UsageHostedService struct{}
+
+func (h *UsageHostedService) Init(b *B, dsn DSN) error        { return nil }
+func (h *UsageHostedService) Start(ctx context.Context) error { return nil }
+func (h *UsageHostedService) Stop(ctx context.Context) error  { return nil }
+
+func getUsageContainer() *Container {
+	c := &Container{}
+	AddHostedService[UsageHostedService](c)
+	AddSingletonWithoutInterface[B](c)
+	AddTransient[AInterface, A](c)
+	AddSingleton[CounterInterface, Counter](c)
+	AddScoped[CInterface, C](c)
+	AddTransientWithoutInterface[D](c)
+	AddValue(c, DSN("postgres://localhost"))
+	AddValue(c, &Config{Host: "unused"})
+	return c
+}
+
+func TestAnalyzeUsage(t *testing.T) {
+	c := getUsageContainer()
+	c.Build()
+
+	report := c.AnalyzeUsage()
+	if !slices.Equal(report.Unreachable, []string{"container.C", "container.D"}) {
+		t.Errorf("Unexpected unreachable services %v", report.Unreachable)
+	}
+	if !slices.Equal(report.UnusedAliases, []UnusedAlias{{"container.CInterface", "container.C"}}) {
+		t.Errorf("Unexpected unused aliases %v", report.UnusedAliases)
+	}
+	if !slices.Equal(report.UnusedValues, []string{"*container.Config"}) {
+		t.Errorf("Unexpected unused values %v", report.UnusedValues)
+	}
+	if report.Empty() {
+		t.Error("Expected a non-empty report")
+	}
+}
+
+func TestAnalyzeUsageEntryPoints(t *testing.T) {
+	c := getUsageContainer()
+	AddEntryPoint[*D](c)
+	AddEntryPoint[*Config](c)
+	c.Build()
+
+	report := c.AnalyzeUsage()
+	if !report.Empty() {
+		t.Errorf("Expected every registration to be used, got %s", report)
+	}
+}
+
+func TestStrictUsageFailsBuild(t *testing.T) {
+	c := getUsageContainer()
+	c.SetStrictUsage(true)
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, ErrUnusedRegistration) {
+			t.Fatalf("Expected ErrUnusedRegistration panic, got %v", r)
+		}
+		expected := "unused registration: unreachable services container.C, container.D; " +
+			"unused aliases container.CInterface -> container.C; unused values *container.Config"
+		if err.Error() != expected {
+			t.Errorf("Expected %q, got %q", expected, err.Error())
+		}
+	}()
+	c.Build()
+}
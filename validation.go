@@ -0,0 +1,215 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IValidatable is implemented by services and configuration structs that check their
+// own invariants.
+//
+// The container calls Validate right after a successful Init. Nested struct fields
+// implementing IValidatable are validated too, but not pointer or interface fields,
+// which hold other services.
+type IValidatable interface {
+	Validate() error
+}
+
+// ValidationError describes a field that violates a `validate` struct tag rule or
+// a Validate method that returned an error.
+//
+// Supported rules, separated by commas in the tag:
+//   - required: the field must not be the zero value
+//   - min=N, max=N: bounds of numbers, or of the length of strings, slices and maps
+//   - oneof=a b c: the field must be one of the space separated values
+type ValidationError struct {
+	Field string
+	Rule  string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	if e.Rule == "" {
+		return fmt.Sprintf("%s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Field, e.Rule, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// needsValidation reports whether values of type t implement IValidatable or have
+// `validate` struct tags, so the container can skip validation of other types.
+func needsValidation(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(reflect.TypeFor[IValidatable]()) || reflect.PointerTo(t).Implements(reflect.TypeFor[IValidatable]()) {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("validate"); ok || field.Type.Kind() == reflect.Struct && needsValidation(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateValue checks the `validate` struct tags of value and calls Validate of value
+// and of its nested struct fields. All violations are returned joined.
+//
+// Pointer and interface fields are not followed: they hold other services, which the
+// container validated when it constructed them.
+func validateValue(value any) error {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var errs []error
+	validateReflect(v, "", &errs)
+	return errors.Join(errs...)
+}
+
+func validateReflect(v reflect.Value, path string, errs *[]error) {
+	var validatable IValidatable
+	if v.CanAddr() {
+		validatable, _ = v.Addr().Interface().(IValidatable)
+	} else if v.CanInterface() {
+		validatable, _ = v.Interface().(IValidatable)
+	}
+	if validatable != nil {
+		if err := validatable.Validate(); err != nil {
+			*errs = append(*errs, &ValidationError{Field: path, Err: err})
+		}
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		fieldPath := structField.Name
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		field := v.Field(i)
+		if tag, ok := structField.Tag.Lookup("validate"); ok {
+			for rule := range strings.SplitSeq(tag, ",") {
+				if rule = strings.TrimSpace(rule); rule == "" {
+					continue
+				}
+				if err := checkRule(field, rule); err != nil {
+					*errs = append(*errs, &ValidationError{Field: fieldPath, Rule: rule, Err: err})
+				}
+			}
+		}
+		if field.Kind() == reflect.Struct {
+			validateReflect(field, fieldPath, errs)
+		}
+	}
+}
+
+func checkRule(field reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if field.IsZero() {
+			return errors.New("value is required")
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid limit %q", arg)
+		}
+		size, ok := measure(field)
+		if !ok {
+			return fmt.Errorf("rule is not supported for kind %s", field.Kind())
+		}
+		if name == "min" && size < limit {
+			return fmt.Errorf("%v is less than %v", size, limit)
+		}
+		if name == "max" && size > limit {
+			return fmt.Errorf("%v is greater than %v", size, limit)
+		}
+	case "oneof":
+		allowed := strings.Fields(arg)
+		if !slices.Contains(allowed, fmt.Sprint(field.Interface())) {
+			return fmt.Errorf("%v is not one of [%s]", field.Interface(), strings.Join(allowed, " "))
+		}
+	default:
+		return fmt.Errorf("unknown rule %q", name)
+	}
+	return nil
+}
+
+// measure returns the number of a numeric field or the length of a string, slice or map.
+func measure(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return float64(field.Len()), true
+	default:
+		return 0, false
+	}
+}
+
+// Validate checks the container without starting hosted services.
+//
+// It constructs all singletons, which validates them after Init, and validates every
+// value registered with AddValue, Configure or ConfigureMonitor. All failures are
+// returned joined; each wraps ErrFailedToBuildDependency and names the service and
+// the field path.
+func (c *Container) Validate() error {
+	if !c.built {
+		return fmt.Errorf("%w: You should call Build() before Validate()", ErrContainerNotBuilt)
+	}
+
+	var errs []error
+	if err := c.Warmup(context.Background(), 1); err != nil {
+		errs = append(errs, err)
+	}
+
+	var values []callSiteInterface
+	for _, site := range c.callSitesRegistry {
+		if site.Lifetime() == Value && !slices.Contains(values, site) {
+			values = append(values, site)
+		}
+	}
+	slices.SortFunc(values, func(a, b callSiteInterface) int { return strings.Compare(a.Name(), b.Name()) })
+	for _, site := range values {
+		instance, _ := site.Build(context.Background(), c.global)
+		if err := validateValue(instance); err != nil {
+			errs = append(errs, fmt.Errorf("%w: for %s: %w", ErrFailedToBuildDependency, site.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
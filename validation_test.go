@@ -0,0 +1,137 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type PoolConfig struct {
+	Size    int      `validate:"min=1,max=10"`
+	Mode    string   `validate:"required,oneof=fast safe"`
+	Servers []string `validate:"min=1"`
+}
+
+type PoolService struct {
+	Config PoolConfig
+	ready  bool
+}
+
+func (p *PoolService) Init(config *Options[PoolConfig]) error {
+	p.Config = config.Value
+	return nil
+}
+
+func (p *PoolService) Validate() error {
+	if !p.ready {
+		return fmt.Errorf("pool is not ready")
+	}
+	return nil
+}
+
+type TaggedService struct {
+	Name string `validate:"required"`
+}
+
+func (t *TaggedService) Init() error { return nil }
+
+func TestValidateTagsAfterInit(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[TaggedService](c)
+	c.Build()
+
+	_, err := RequireServicePtr[TaggedService](c)
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrFailedToBuildDependency) || !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ErrFailedToBuildDependency with ValidationError, got %v", err)
+	}
+	if validationErr.Field != "Name" || validationErr.Rule != "required" {
+		t.Errorf("Unexpected validation error: %+v", validationErr)
+	}
+}
+
+func TestValidateMethodAfterInit(t *testing.T) {
+	c := &Container{}
+	AddValue(c, &Options[PoolConfig]{Value: PoolConfig{Size: 1, Mode: "fast", Servers: []string{"a"}}})
+	AddTransientWithoutInterface[PoolService](c)
+	c.Build()
+
+	_, err := RequireServicePtr[PoolService](c)
+	if !errors.Is(err, ErrFailedToBuildDependency) || !strings.Contains(err.Error(), "pool is not ready") {
+		t.Errorf("Expected Validate failure, got %v", err)
+	}
+}
+
+type CountedValidation struct{ validations int }
+
+func (d *CountedValidation) Init() error     { return nil }
+func (d *CountedValidation) Validate() error { d.validations++; return nil }
+
+type DependsOnCountedValidation struct {
+	Dep  *CountedValidation
+	Name string `validate:"required"`
+}
+
+func (d *DependsOnCountedValidation) Init(dep *CountedValidation) error {
+	d.Dep, d.Name = dep, "dependent"
+	return nil
+}
+
+func TestValidateDoesNotFollowDependencies(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[CountedValidation](c)
+	AddTransientWithoutInterface[DependsOnCountedValidation](c)
+	c.Build()
+
+	for range 3 {
+		if _, err := RequireServicePtr[DependsOnCountedValidation](c); err != nil {
+			t.Fatalf("Failed to resolve DependsOnCountedValidation: %v", err)
+		}
+	}
+	dep, _ := RequireServicePtr[CountedValidation](c)
+	if dep.validations != 1 {
+		t.Errorf("Expected the dependency to be validated once when constructed, got %d", dep.validations)
+	}
+}
+
+func TestContainerValidate(t *testing.T) {
+	c := &Container{}
+	AddValue(c, &Options[PoolConfig]{Value: PoolConfig{Size: 20, Mode: "slow"}})
+	AddSingletonWithoutInterface[TaggedService](c)
+	AddHostedService[MyHostedService](c)
+	c.Build()
+
+	err := c.Validate()
+	if !errors.Is(err, ErrFailedToBuildDependency) {
+		t.Fatalf("Expected ErrFailedToBuildDependency, got %v", err)
+	}
+	for _, expected := range []string{
+		"Value.Size: max=10: 20 is greater than 10",
+		"Value.Mode: oneof=fast safe: slow is not one of [fast safe]",
+		"Value.Servers: min=1: 0 is less than 1",
+		"Name: required: value is required",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %v", expected, err)
+		}
+	}
+
+	hosted, _ := RequireServicePtr[MyHostedService](c)
+	if hosted.started {
+		t.Errorf("Validate should not start hosted services")
+	}
+}
+
+func TestConfigureValidateTags(t *testing.T) {
+	t.Setenv("POOL_SIZE", "0")
+	t.Setenv("POOL_MODE", "fast")
+	err := Configure[PoolConfig](&Container{}, EnvSource("POOL"))
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrInvalidConfiguration) || !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ErrInvalidConfiguration with ValidationError, got %v", err)
+	}
+	if validationErr.Field != "Size" {
+		t.Errorf("Expected Size field, got %+v", validationErr)
+	}
+}
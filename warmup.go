@@ -0,0 +1,67 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// uniqueSites returns every call site once, ordered by name.
+func (c *Container) uniqueSites() []callSiteInterface {
+	var sites []callSiteInterface
+	seen := make(map[callSiteInterface]struct{})
+	for _, site := range c.registrations() {
+		if _, ok := seen[site]; !ok {
+			seen[site] = struct{}{}
+			sites = append(sites, site)
+		}
+	}
+	slices.SortFunc(sites, func(a, b callSiteInterface) int { return strings.Compare(a.Name(), b.Name()) })
+	return sites
+}
+
+// singletonSites returns every singleton call site once, ordered by name.
+func (c *Container) singletonSites() []callSiteInterface {
+	return slices.DeleteFunc(c.uniqueSites(), func(site callSiteInterface) bool {
+		return site.Lifetime() != Singleton
+	})
+}
+
+// Warmup constructs all singleton services in dependency order.
+//
+// Singletons are normally built on first resolution, so a failing Init method surfaces
+// only when a code path first requires the service. Calling Warmup right after Build
+// surfaces such failures at startup. Singletons whose dependencies are constructed are
+// built concurrently, with at most maxConcurrency constructions at once; a maxConcurrency
+// of one builds them sequentially and zero or less means no limit.
+//
+// Warmup does not stop at the first failure. It returns the errors of all singletons that
+// could not be constructed, joined in name order. Each of them wraps a *ResolutionError
+// carrying the dependency path from the singleton to the service that failed.
+func (c *Container) Warmup(ctx context.Context, maxConcurrency int) error {
+	if !c.built {
+		return fmt.Errorf("%w: You should call Build() before Warmup()", ErrContainerNotBuilt)
+	}
+
+	sites := c.singletonSites()
+	errs := make([]error, len(sites))
+	for _, wave := range c.graph.waves(sites) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_ = runParallel(ctx, wave, maxConcurrency, func(ctx context.Context, i int) error {
+			_, errs[i] = sites[i].Build(ctx, c.global)
+			return nil
+		})
+	}
+
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("warmup of %s failed: %w", sites[i].Name(), err))
+		}
+	}
+	return errors.Join(failures...)
+}
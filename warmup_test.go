@@ -0,0 +1,87 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+var warmupInits atomic.Int32
+
+type WarmupLeaf struct{}
+
+func (w *WarmupLeaf) Init() error {
+	warmupInits.Add(1)
+	return nil
+}
+
+type WarmupRoot struct{ leaf *WarmupLeaf }
+
+func (w *WarmupRoot) Init(leaf *WarmupLeaf) error {
+	warmupInits.Add(1)
+	w.leaf = leaf
+	return nil
+}
+
+func TestWarmup(t *testing.T) {
+	warmupInits.Store(0)
+	c := &Container{}
+	AddSingletonWithoutInterface[WarmupRoot](c)
+	AddSingletonWithoutInterface[WarmupLeaf](c)
+	c.Build()
+
+	if warmupInits.Load() != 0 {
+		t.Fatalf("Singletons should not be built before Warmup")
+	}
+	if err := c.Warmup(context.Background(), 0); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if warmupInits.Load() != 2 {
+		t.Errorf("Expected 2 singletons to be built, got %d", warmupInits.Load())
+	}
+
+	if _, err := RequireServicePtr[WarmupRoot](c); err != nil {
+		t.Errorf("Failed to resolve WarmupRoot: %v", err)
+	}
+	if warmupInits.Load() != 2 {
+		t.Errorf("Resolution after Warmup should reuse singletons, got %d inits", warmupInits.Load())
+	}
+}
+
+type WarmupDependsOnFailing struct{}
+
+func (w *WarmupDependsOnFailing) Init(f *ServiceWithFailingInit) error { return nil }
+
+func TestWarmupReportsAllFailures(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[ServiceWithFailingInit](c)
+	AddSingletonWithoutInterface[WarmupDependsOnFailing](c)
+	AddSingletonWithoutInterface[WarmupLeaf](c)
+	c.Build()
+
+	err := c.Warmup(context.Background(), 1)
+	if !errors.Is(err, ErrFailedToBuildDependency) {
+		t.Fatalf("Expected ErrFailedToBuildDependency, got %v", err)
+	}
+	failures := strings.Split(err.Error(), "\nwarmup of ")
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %q", err.Error())
+	}
+	if !strings.HasPrefix(failures[0], "warmup of container.ServiceWithFailingInit failed") {
+		t.Errorf("Unexpected first failure: %s", failures[0])
+	}
+	if !strings.HasPrefix(failures[1], "container.WarmupDependsOnFailing failed") ||
+		!strings.Contains(failures[1], "-> container.ServiceWithFailingInit (Singleton)") {
+		t.Errorf("Unexpected second failure: %s", failures[1])
+	}
+}
+
+func TestWarmupBeforeBuild(t *testing.T) {
+	c := &Container{}
+	AddSingletonWithoutInterface[WarmupLeaf](c)
+	if err := c.Warmup(context.Background(), 0); !errors.Is(err, ErrContainerNotBuilt) {
+		t.Errorf("Expected ErrContainerNotBuilt, got %v", err)
+	}
+}